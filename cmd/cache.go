@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/FrangipaneTeam/bean/internal/cache"
+)
+
+var (
+	cacheAll    bool
+	cacheOutput string
+
+	cacheCmd = &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the cache of the parsed examples and CRDs",
+		Long: `The examples and the CRDs parsed by bean are kept in an index per provider under the user cache dir,
+a file is parsed again only when it changes. The TUI, the commands and the shell completion share the index.`,
+	}
+
+	cacheClearCmd = &cobra.Command{
+		Use:          "clear",
+		Short:        "Remove the cache of the provider path",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			removed, err := cache.Clear(c.Path, cacheAll)
+			for _, r := range removed {
+				fmt.Fprintf(cmd.OutOrStdout(), "removed %s\n", r)
+			}
+			if err != nil {
+				return err
+			}
+			if len(removed) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "nothing to remove")
+			}
+			return nil
+		},
+	}
+
+	cacheStatsCmd = &cobra.Command{
+		Use:          "stats",
+		Short:        "Show the cache of the provider path",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkCacheOutput(); err != nil {
+				return err
+			}
+			s, err := cache.ReadStats(c.Path)
+			if err != nil {
+				return err
+			}
+			if cacheOutput == outputJSON {
+				return writeJSON(cmd.OutOrStdout(), s)
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+			fmt.Fprintf(w, "index\t%s\t%s\n", s.Index.Path, formatFileStats(s.Index))
+			fmt.Fprintf(w, "  examples\t%d\t%d stale\n", s.Examples, s.StaleExamples)
+			fmt.Fprintf(w, "  CRDs\t%d\t%d stale\n", s.CRDs, s.StaleCRDs)
+			fmt.Fprintf(w, "all providers\t%s\t%d file(s), %s\n", s.Dir, s.Files, formatSize(s.Size))
+			return w.Flush()
+		},
+	}
+)
+
+func checkCacheOutput() error {
+	if cacheOutput != outputText && cacheOutput != outputJSON {
+		return fmt.Errorf("unknown output %s, expected %s or %s", cacheOutput, outputText, outputJSON)
+	}
+	return nil
+}
+
+// formatFileStats returns the size and the update time of an index file.
+func formatFileStats(f cache.FileStats) string {
+	if f.ModTime == nil {
+		return "not cached"
+	}
+	return fmt.Sprintf("%s, updated %s", formatSize(f.Size), f.ModTime.Format("2006-01-02 15:04:05"))
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/FrangipaneTeam/bean/config"
+	"github.com/FrangipaneTeam/bean/internal/completion"
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+)
+
+// completeExamples completes the example paths, example-ids and directories of the provider path.
+func completeExamples(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	loaded, err := completionExamples()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return completion.ExampleCandidates(loaded, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeExample completes the single example of the command.
+func completeExample(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeExamples(cmd, args, toComplete)
+}
+
+// completeKind completes the single CRD kind of the command.
+func completeKind(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	loaded, err := completionExamples()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return completion.KindCandidates(loaded.CRDs, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionExamples returns the examples of the provider path of the command line, the files changed since
+// the previous run are parsed again. The config is loaded again, the persistent pre run is not run for the
+// completion and the config files were merged before the --path flag was parsed.
+func completionExamples() (exlist.LoadedExamples, error) {
+	files, err := config.Files(c.Path, cfgFile)
+	if err != nil {
+		return exlist.LoadedExamples{}, err
+	}
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err = config.Merge(v, files); err != nil {
+		return exlist.LoadedExamples{}, err
+	}
+	c.Viper = v
+	return loadExamples()
+}
+
+// isCompletion returns true if bean is run by the shell to complete its command line.
+func isCompletion() bool {
+	return len(os.Args) > 1 && (os.Args[1] == cobra.ShellCompRequestCmd || os.Args[1] == cobra.ShellCompNoDescRequestCmd)
+}
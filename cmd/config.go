@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/FrangipaneTeam/bean/config"
+)
+
+var (
+	configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Show and check the bean config",
+		Long: `The config is merged from ~/.bean.yaml, the .bean.yaml of the provider path and the --config file,
+each one overriding the previous ones.`,
+	}
+
+	configPrintCmd = &cobra.Command{
+		Use:          "print",
+		Short:        "Print the effective config and where each value comes from",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+			for _, s := range config.Effective(c.Files) {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", s.Key, formatValue(s.Value), s.Source)
+			}
+			return w.Flush()
+		},
+	}
+
+	configValidateCmd = &cobra.Command{
+		Use:          "validate",
+		Short:        "Check the config files against the config schema",
+		SilenceUsage: true,
+		// the config files are read again to report their errors
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := config.Files(c.Path, cfgFile)
+			if err != nil {
+				return err
+			}
+
+			errs := []error{}
+			for _, f := range files {
+				fmt.Fprintf(cmd.OutOrStdout(), "checking %s\n", f.Path)
+				errs = append(errs, f.Validate()...)
+			}
+
+			for _, err := range errs {
+				fmt.Fprintf(cmd.OutOrStdout(), "  ✗ %s\n", err)
+			}
+			if len(errs) > 0 {
+				return errors.New("invalid config")
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "%d config file(s) valid\n", len(files))
+			return nil
+		},
+	}
+)
+
+// formatValue returns the value as a string, lists and objects in JSON.
+func formatValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
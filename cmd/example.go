@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+	"github.com/FrangipaneTeam/bean/internal/kube"
+	"github.com/FrangipaneTeam/bean/internal/results"
+	"github.com/FrangipaneTeam/bean/tui/pages/errorpanel"
+	"github.com/FrangipaneTeam/bean/tui/pages/k8s"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+
+	// waitInterval is the time between two checks of the resources waited for, as in the TUI.
+	waitInterval = 2 * time.Second
+)
+
+var (
+	exampleWithDeps bool
+	exampleNoDeps   bool
+	exampleDryRun   bool
+	exampleWait     bool
+	exampleOutput   string
+
+	applyCmd = &cobra.Command{
+		Use:   "apply <example>",
+		Short: "Apply an example with its dependencies",
+		Long: `Apply the files of the example, its extra and secret files, after its dependencies layer by layer, waiting
+for the managed resources of each layer to be Ready and Synced as the TUI does, the example's included unless
+--wait=false is set. The readiness is recorded in the run results. The example is a path or an example-id.
+--dry-run prints the kubectl commands instead.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeExample,
+		SilenceUsage:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExample(cmd, args[0], "apply")
+		},
+	}
+
+	deleteCmd = &cobra.Command{
+		Use:   "delete <example>",
+		Short: "Delete an example with its dependencies",
+		Long: `Delete the files of the example, its extra and secret files, then its dependencies layer by layer in
+reverse order, waiting for the resources of each layer to be gone as the TUI does. The example is a path or an
+example-id. --dry-run prints the kubectl commands instead.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeExample,
+		SilenceUsage:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExample(cmd, args[0], "delete")
+		},
+	}
+
+	getCmd = &cobra.Command{
+		Use:   "get <example>",
+		Short: "Get the resources of an example and its dependencies",
+		Long: `Get the current state of the resources of the example and its dependencies from the cluster.
+The example is a path or an example-id. --dry-run prints the kubectl command instead.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeExample,
+		SilenceUsage:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExample(cmd, args[0], "get")
+		},
+	}
+
+	depsCmd = &cobra.Command{
+		Use:   "deps <example>",
+		Short: "Print the files applied for an example",
+		Long: `Print the files applied for the example in apply order, one per line : the files of its dependencies,
+then the example and its extra and secret files. The JSON output has the layers applied one after the other.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeExample,
+		SilenceUsage:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutput(); err != nil {
+				return err
+			}
+
+			e, err := resolveExample(args[0])
+			if err != nil {
+				return err
+			}
+			k8sCmd, err := k8s.NewCmd(e, "", withDependencies(), c.WaitTimeout)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if exampleOutput == outputJSON {
+				return writeJSON(out, newExampleResult(k8sCmd))
+			}
+			for _, f := range k8sCmd.Files {
+				fmt.Fprintln(out, f)
+			}
+			return nil
+		},
+	}
+)
+
+// exampleResult is the JSON output of the example commands.
+type exampleResult struct {
+	Example   string     `json:"example"`
+	ExampleID string     `json:"exampleId,omitempty"`
+	Verb      string     `json:"verb,omitempty"`
+	Files     []string   `json:"files"`
+	Layers    [][]string `json:"layers"`
+	// Commands are the kubectl commands of a dry run.
+	Commands []string `json:"commands,omitempty"`
+	// Objects are the objects applied or deleted.
+	Objects []objectRef `json:"objects,omitempty"`
+	// Resources are the resources got from the cluster, or waited for after the apply.
+	Resources []kube.ResourceStatus `json:"resources,omitempty"`
+}
+
+type objectRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+}
+
+func newExampleResult(k8sCmd *k8s.Cmd) exampleResult {
+	return exampleResult{
+		Example:   k8sCmd.Example.FullPath,
+		ExampleID: k8sCmd.Example.ExampleID,
+		Verb:      k8sCmd.Verb,
+		Files:     k8sCmd.Files,
+		Layers:    k8sCmd.Layers,
+	}
+}
+
+// withDependencies returns true unless --no-deps is set.
+func withDependencies() bool {
+	return exampleWithDeps && !exampleNoDeps
+}
+
+func checkOutput() error {
+	if exampleOutput != outputText && exampleOutput != outputJSON {
+		return fmt.Errorf("unknown output %s, expected %s or %s", exampleOutput, outputText, outputJSON)
+	}
+	return nil
+}
+
+// resolveExample returns the example of the provider path matching the path or example-id.
+func resolveExample(pattern string) (*exlist.Example, error) {
+	loaded, err := loadExamples()
+	if err != nil {
+		return nil, err
+	}
+	return exlist.Resolve(loaded.Base, loaded.List(), pattern)
+}
+
+// runExample runs the verb on the files of the example, as the TUI does.
+func runExample(cmd *cobra.Command, pattern, verb string) error {
+	if err := checkOutput(); err != nil {
+		return err
+	}
+
+	e, err := resolveExample(pattern)
+	if err != nil {
+		return err
+	}
+	k8sCmd, err := k8s.NewCmd(e, verb, withDependencies(), c.WaitTimeout)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if withDependencies() && e.DependenciesWarning != "" {
+		fmt.Fprintln(cmd.ErrOrStderr(), "⚠", e.DependenciesWarning)
+	}
+	res := newExampleResult(k8sCmd)
+	if exampleDryRun {
+		res.Commands = k8s.KubectlCommands(e, verb, withDependencies())
+		if exampleOutput == outputJSON {
+			return writeJSON(out, res)
+		}
+		fmt.Fprintln(out, strings.Join(res.Commands, "\n"))
+		return nil
+	}
+
+	executor, err := k8s.NewExecutor(c)
+	if err != nil {
+		return fmt.Errorf("could not create kubernetes client: %w", err)
+	}
+	k8sCmd.Executor = executor
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	if verb == "get" {
+		return getExample(ctx, out, k8sCmd, res)
+	}
+
+	store := results.New(c)
+	switch msg := k8s.Kubectl(ctx, k8sCmd)().(type) {
+	case errorpanel.ErrorMsg:
+		err = fmt.Errorf("%s: %w", msg.Reason, msg.Cause)
+		if errRecord := store.Record(e.FullPath, verb, err); errRecord != nil {
+			return fmt.Errorf("%w, could not record the result: %s", err, errRecord)
+		}
+		return err
+	case *k8s.Cmd:
+		if err = store.Record(e.FullPath, verb, nil); err != nil {
+			return fmt.Errorf("could not record the result: %w", err)
+		}
+		res.Objects = objectRefs(msg.Objects)
+		if exampleOutput == outputText {
+			fmt.Fprint(out, msg.Result)
+		}
+		if verb == "apply" && exampleWait {
+			err = waitReady(ctx, out, k8sCmd, msg.Objects, &res)
+			if errRecord := store.Record(e.FullPath, results.StepReady, err); errRecord != nil && err == nil {
+				err = fmt.Errorf("could not record the result: %w", errRecord)
+			}
+		}
+		if exampleOutput == outputJSON {
+			if errWrite := writeJSON(out, res); errWrite != nil {
+				return errWrite
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// waitReady waits for the managed resources of the example applied by the command to be Ready and Synced,
+// its dependencies are ready.
+func waitReady(ctx context.Context, out io.Writer, k8sCmd *k8s.Cmd, objs []*unstructured.Unstructured, res *exampleResult) error {
+	waitCtx, cancel := context.WithTimeout(ctx, c.WaitTimeout)
+	defer cancel()
+	statuses, err := kube.WaitReady(waitCtx, k8sCmd.Executor, objs, waitInterval)
+	res.Resources = statuses
+	if err != nil {
+		return fmt.Errorf("wait ready: %w", err)
+	}
+	if exampleOutput == outputText {
+		fmt.Fprintf(out, "%d managed resource(s) ready\n", len(statuses))
+	}
+	return nil
+}
+
+// getExample writes the current state of the objects of the files of the command.
+func getExample(ctx context.Context, out io.Writer, k8sCmd *k8s.Cmd, res exampleResult) error {
+	objs, err := k8s.ReadObjects(c, k8sCmd.Files)
+	if err != nil {
+		return err
+	}
+
+	found := []*unstructured.Unstructured{}
+	missing := []string{}
+	res.Resources = []kube.ResourceStatus{}
+	for _, obj := range objs {
+		current, errGet := k8sCmd.Executor.Get(ctx, obj)
+		switch {
+		case apierrors.IsNotFound(errGet):
+			s := kube.ResourceStatus{Kind: obj.GetKind(), Name: obj.GetName()}
+			res.Resources = append(res.Resources, s)
+			missing = append(missing, s.String())
+			continue
+		case errGet != nil:
+			return errGet
+		}
+		found = append(found, current)
+		res.Resources = append(res.Resources, kube.Status(current))
+	}
+
+	if exampleOutput == outputJSON {
+		return writeJSON(out, res)
+	}
+	if len(found) > 0 || len(missing) == 0 {
+		fmt.Fprintln(out, strings.TrimSuffix(kube.FormatManaged(found), "\n"))
+	}
+	for _, m := range missing {
+		fmt.Fprintf(out, "%s not found\n", m)
+	}
+	return nil
+}
+
+func objectRefs(objs []*unstructured.Unstructured) []objectRef {
+	refs := make([]objectRef, 0, len(objs))
+	for _, obj := range objs {
+		refs = append(refs, objectRef{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Name:       obj.GetName(),
+			Namespace:  obj.GetNamespace(),
+		})
+	}
+	return refs
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+)
+
+var (
+	graphFormat string
+	graphOutput string
+
+	graphCmd = &cobra.Command{
+		Use:   "graph [directory|glob|example-id]...",
+		Short: "Export the dependency graph of the examples",
+		Long: fmt.Sprintf(`Export the dependency graph of the selected examples, or of all the examples, as %s.
+Each edge is labelled with the fields that caused it.`, strings.Join(exlist.GraphFormats, ", ")),
+		SilenceUsage:      true,
+		ValidArgsFunction: completeExamples,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			loaded, err := loadExamples()
+			if err != nil {
+				return err
+			}
+
+			paths := []string{}
+			if len(args) > 0 {
+				selected, errSelect := exlist.Select(loaded.Base, loaded.List(), args)
+				if errSelect != nil {
+					return errSelect
+				}
+				for _, e := range selected {
+					paths = append(paths, e.FullPath)
+				}
+			}
+
+			var w io.Writer = cmd.OutOrStdout()
+			if graphOutput != "-" {
+				f, errCreate := os.Create(graphOutput)
+				if errCreate != nil {
+					return errCreate
+				}
+				defer f.Close()
+				w = f
+			}
+
+			name := exlist.RelativeName(loaded.Base)
+			return loaded.Graph.WriteGraph(w, graphFormat, name, paths...)
+		},
+	}
+)
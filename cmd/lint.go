@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+	"github.com/FrangipaneTeam/bean/internal/lint"
+)
+
+var (
+	lintFormat string
+	lintRules  bool
+
+	lintCmd = &cobra.Command{
+		Use:   "lint [directory|glob|example-id]...",
+		Short: "Validate the examples against the schemas of their CRDs",
+		Long: `Validate every document of the selected examples, or of all the examples, against the openAPIV3Schema
+of its CRD : unknown fields, wrong types, missing required fields, values out of their enum and kinds or versions
+without CRD, and the conventions of the upjet examples : example-id, duplicated names and labels, dangling refs
+and selectors and missing providerConfigRef. The rules listed by --rules can be disabled by the lint.disabled key
+of the config. Exits with an error if an issue is found.`,
+		SilenceUsage:      true,
+		ValidArgsFunction: completeExamples,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if lintRules {
+				printRules(cmd.OutOrStdout())
+				return nil
+			}
+
+			loaded, err := loadExamples()
+			if err != nil {
+				return err
+			}
+			// the examples are loaded without validation if the CRDs can't be read
+			if loaded.CRDsErr != nil {
+				return fmt.Errorf("could not read the CRDs: %w", loaded.CRDsErr)
+			}
+
+			selected := loaded.List()
+			if len(args) > 0 {
+				if selected, err = exlist.Select(loaded.Base, selected, args); err != nil {
+					return err
+				}
+			}
+
+			diags := []lint.Diagnostic{}
+			for _, e := range selected {
+				for _, d := range e.Diagnostics {
+					if rel, errRel := filepath.Rel(c.Path, d.File); errRel == nil {
+						d.File = rel
+					}
+					diags = append(diags, d)
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			switch lintFormat {
+			case "json":
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err = enc.Encode(diags); err != nil {
+					return err
+				}
+			case "text":
+				for _, d := range diags {
+					fmt.Fprintln(out, d)
+				}
+				fmt.Fprintf(out, "%d example(s) checked, %d issue(s)\n", len(selected), len(diags))
+			default:
+				return fmt.Errorf("unknown format %s, expected text or json", lintFormat)
+			}
+
+			if len(diags) > 0 {
+				return fmt.Errorf("%d lint issue(s)", len(diags))
+			}
+			return nil
+		},
+	}
+)
+
+// printRules prints the lint rules and whether they are disabled by the config.
+func printRules(out io.Writer) {
+	disabled := map[string]bool{}
+	for _, r := range c.DisabledRules() {
+		disabled[r] = true
+	}
+
+	rules := make([]string, 0, len(lint.Rules))
+	for r := range lint.Rules {
+		rules = append(rules, string(r))
+	}
+	sort.Strings(rules)
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "RULE\tSTATE\tDESCRIPTION")
+	for _, r := range rules {
+		state := "enabled"
+		if disabled[r] {
+			state = "disabled"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r, state, lint.Rules[lint.Rule(r)])
+	}
+	w.Flush()
+}
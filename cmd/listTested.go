@@ -2,15 +2,107 @@
 package cmd
 
 import (
-	"github.com/FrangipaneTeam/bean/tui/pages/md"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
 	"github.com/spf13/cobra"
+
+	"github.com/FrangipaneTeam/bean/internal/coverage"
+	"github.com/FrangipaneTeam/bean/tui/pages/md"
+)
+
+var (
+	listTestedFormat   string
+	listTestedOutput   string
+	listTestedCheck    bool
+	listTestedBaseline string
+	listTestedUpdate   bool
+
+	listTestedCmd = &cobra.Command{
+		Use:   "listTested",
+		Short: "Generate a list of tested resources",
+		Long: fmt.Sprintf(`Generate the list of the CRDs kinds with their versions, whether an example applies them and the
+example files that do, as %v.
+Without --output, the markdown list is written to %s in the provider path and the other formats to stdout.
+
+--check compares the coverage with the baseline, %s in the provider path by default, and exits with an error
+if a tested kind became untested, a new kind has no example or a group is below its minimum coverage.
+The kinds listed as untested by the baseline are known gaps. --update-baseline writes the current coverage
+to the baseline, keeping its minimum coverages.`,
+			coverage.Formats, md.ListTestedFile, coverage.BaselineFile),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := coverage.ParseFormat(listTestedFormat)
+			if err != nil {
+				return err
+			}
+
+			report, err := md.ListTested(c)
+			if err != nil {
+				return err
+			}
+
+			if listTestedCheck || listTestedUpdate {
+				return checkBaseline(cmd.OutOrStdout(), report)
+			}
+
+			output := listTestedOutput
+			if output == "" && format == coverage.FormatMarkdown {
+				output = filepath.Join(c.Path, md.ListTestedFile)
+			}
+
+			var w io.Writer = cmd.OutOrStdout()
+			if output != "" && output != "-" {
+				f, errCreate := os.Create(output)
+				if errCreate != nil {
+					return errCreate
+				}
+				defer f.Close()
+				w = f
+			}
+
+			return coverage.Write(w, format, report)
+		},
+	}
 )
 
-var listTestedCmd = &cobra.Command{
-	Use:   "listTested",
-	Short: "Generate a list of tested resources",
-	Long:  `Generate a list of tested resources and write it to a file in the current directory named "listTested.md"`,
-	Run: func(cmd *cobra.Command, args []string) {
-		md.GenerateListTested(c)
-	},
+// checkBaseline checks the report against the baseline, or updates the baseline.
+func checkBaseline(w io.Writer, report coverage.Report) error {
+	path := listTestedBaseline
+	if path == "" {
+		path = filepath.Join(c.Path, coverage.BaselineFile)
+	}
+
+	baseline, err := coverage.ReadBaseline(path)
+	if err != nil {
+		return err
+	}
+
+	if listTestedUpdate {
+		if err = coverage.WriteBaseline(path, coverage.NewBaseline(report, baseline.MinCoverage)); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "baseline %s updated, %d/%d kinds tested\n", path, report.Tested, report.Total)
+		return nil
+	}
+
+	if _, errStat := os.Stat(path); errStat != nil {
+		return fmt.Errorf("no baseline %s, create it with --update-baseline", path)
+	}
+
+	regressions, fixed := baseline.Check(report)
+	for _, p := range fixed {
+		fmt.Fprintln(w, p)
+	}
+	for _, p := range regressions {
+		fmt.Fprintln(w, p)
+	}
+	fmt.Fprintf(w, "%d/%d kinds tested, %d regression(s)\n", report.Tested, report.Total, len(regressions))
+
+	if len(regressions) > 0 {
+		return fmt.Errorf("%d coverage regression(s)", len(regressions))
+	}
+	return nil
 }
@@ -13,19 +13,39 @@ import (
 	"github.com/tcnksm/go-latest"
 
 	"github.com/FrangipaneTeam/bean/config"
+	"github.com/FrangipaneTeam/bean/internal/coverage"
+	"github.com/FrangipaneTeam/bean/internal/exlist"
 	"github.com/FrangipaneTeam/bean/tui/pages/loading"
 )
 
 var (
 	c       = config.Provider{}
 	cfgFile string
+	// cfgErr is the error met while loading the config files.
+	cfgErr error
 
 	rootCmd = &cobra.Command{
 		Use:   "bean",
 		Short: "A command-line tool to find username on websites",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if cfgErr != nil {
+				cmd.SilenceUsage = true
+				return cfgErr
+			}
+			c.Viper = viper.GetViper()
+			applyKubeConfig(cmd)
+
+			if !cmd.Flags().Changed("profile") {
+				c.Profile = viper.GetString("profile")
+			}
+			if _, err := c.ProfileVars(); err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			return nil
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			rand.Seed(time.Now().UTC().UnixNano())
-			c.Viper = viper.GetViper()
 			p := tea.NewProgram(loading.New(c))
 
 			if _, err := p.Run(); err != nil {
@@ -39,9 +59,48 @@ var (
 // Execute executes the root command.
 func Execute(version string) {
 	cobra.OnInitialize(initConfig)
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file merged over ~/.bean.yaml and the .bean.yaml of the provider path")
 	rootCmd.PersistentFlags().StringVarP(&c.Path, "path", "p", ".", "your provider path")
 	rootCmd.PersistentFlags().BoolVarP(&c.Debug, "debug", "d", false, "debug mode")
+	rootCmd.PersistentFlags().StringVar(&c.Kubeconfig, "kubeconfig", "", "path to the kubeconfig file")
+	rootCmd.PersistentFlags().StringVar(&c.Profile, "profile", "", "the profile of variables rendered in the examples")
+	rootCmd.PersistentFlags().StringVar(&c.KubeContext, "context", "", "the kubeconfig context to use")
+	rootCmd.PersistentFlags().DurationVar(&c.WaitTimeout, "wait-timeout", config.DefaultWaitTimeout, "time to wait for applied resources to be Ready and Synced")
 	rootCmd.AddCommand(listTestedCmd)
+	listTestedCmd.Flags().StringVarP(&listTestedFormat, "format", "f", string(coverage.FormatMarkdown), "output format: markdown, json, yaml, csv, html or junit")
+	listTestedCmd.Flags().StringVarP(&listTestedOutput, "output", "o", "", "output file, - for stdout")
+	listTestedCmd.Flags().BoolVar(&listTestedCheck, "check", false, "check the coverage against the baseline")
+	listTestedCmd.Flags().StringVar(&listTestedBaseline, "baseline", "", "baseline file, "+coverage.BaselineFile+" in the provider path by default")
+	listTestedCmd.Flags().BoolVar(&listTestedUpdate, "update-baseline", false, "write the current coverage to the baseline")
+	rootCmd.AddCommand(testCmd)
+	testCmd.Flags().StringVar(&junitReport, "junit", "", "write a JUnit XML report to the file")
+	testCmd.Flags().BoolVar(&testNoDeps, "no-deps", false, "do not apply the dependencies files")
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configPrintCmd)
+	configCmd.AddCommand(configValidateCmd)
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	cacheClearCmd.Flags().BoolVar(&cacheAll, "all", false, "remove the cache of all the providers")
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheStatsCmd.Flags().StringVarP(&cacheOutput, "output", "o", outputText, "output format: text or json")
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().StringVarP(&lintFormat, "format", "f", "text", "output format: text or json")
+	lintCmd.Flags().BoolVar(&lintRules, "rules", false, "list the rules and exit")
+	rootCmd.AddCommand(scaffoldCmd)
+	for _, exampleCmd := range []*cobra.Command{applyCmd, deleteCmd, getCmd, depsCmd} {
+		rootCmd.AddCommand(exampleCmd)
+		exampleCmd.Flags().BoolVar(&exampleWithDeps, "with-deps", true, "apply, delete or get the dependencies files too")
+		exampleCmd.Flags().BoolVar(&exampleNoDeps, "no-deps", false, "do not apply, delete or get the dependencies files")
+		exampleCmd.MarkFlagsMutuallyExclusive("with-deps", "no-deps")
+		exampleCmd.Flags().StringVarP(&exampleOutput, "output", "o", outputText, "output format: text or json")
+		if exampleCmd != depsCmd {
+			exampleCmd.Flags().BoolVar(&exampleDryRun, "dry-run", false, "print the kubectl commands without running them")
+		}
+	}
+	applyCmd.Flags().BoolVar(&exampleWait, "wait", true, "wait for the managed resources of the example to be Ready and Synced")
+	rootCmd.AddCommand(graphCmd)
+	graphCmd.Flags().StringVarP(&graphFormat, "format", "f", exlist.GraphFormatDOT, "output format: dot, mermaid or json")
+	graphCmd.Flags().StringVarP(&graphOutput, "output", "o", "-", "output file, - for stdout")
 	c.Version = version
 
 	githubTag := &latest.GithubTag{
@@ -49,35 +108,39 @@ func Execute(version string) {
 		Repository: "bean",
 	}
 
-	res, err := latest.Check(githubTag, c.Version)
-	if err == nil {
-		if res.Outdated {
+	// the completion runs on each TAB, it must not wait for github
+	if !isCompletion() {
+		res, err := latest.Check(githubTag, c.Version)
+		if err == nil && res.Outdated {
 			c.NewVersion = res.Current
 		}
 	}
 
-	err = rootCmd.Execute()
-	if err != nil {
+	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
 }
 
+// initConfig merges the home config, the project config and the --config file into viper.
 func initConfig() {
-	if cfgFile != "" {
-		// Use config file from the flag.
-		viper.SetConfigFile(cfgFile)
-	} else {
-		// Find home directory.
-		home, err := os.UserHomeDir()
-		cobra.CheckErr(err)
-
-		// Search config in home directory with name ".cobra" (without extension).
-		viper.AddConfigPath(home)
-		viper.SetConfigType("yaml")
-		viper.SetConfigName(".bean")
+	c.Files, cfgErr = config.Files(c.Path, cfgFile)
+	if cfgErr != nil {
+		return
 	}
 
-	// viper.AutomaticEnv()
+	viper.SetConfigType("yaml")
+	cfgErr = config.Merge(viper.GetViper(), c.Files)
+}
 
-	viper.ReadInConfig()
+// applyKubeConfig sets the kubernetes settings from the config when their flag is not set.
+func applyKubeConfig(cmd *cobra.Command) {
+	if !cmd.Flags().Changed("kubeconfig") {
+		c.Kubeconfig = viper.GetString("kube.kubeconfig")
+	}
+	if !cmd.Flags().Changed("context") {
+		c.KubeContext = viper.GetString("kube.context")
+	}
+	if timeout := viper.GetDuration("kube.waitTimeout"); !cmd.Flags().Changed("wait-timeout") && timeout > 0 {
+		c.WaitTimeout = timeout
+	}
 }
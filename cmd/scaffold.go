@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/FrangipaneTeam/bean/internal/scaffold"
+)
+
+var scaffoldCmd = &cobra.Command{
+	Use:   "scaffold <kind|group/kind>",
+	Short: "Generate the example of a kind from the schema of its CRD",
+	Long: `Generate examples/<group>/<kind>.yaml from the schema of the storage version of the CRD : the required
+forProvider fields set to placeholders of their type, the example-id annotation, the example-name label and
+selector stubs for the ids of other resources. An existing file is never overwritten.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeKind,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := scaffold.Kind(c, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "created %s\n", path)
+		return nil
+	},
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/FrangipaneTeam/bean/internal/examples"
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+	"github.com/FrangipaneTeam/bean/internal/results"
+	"github.com/FrangipaneTeam/bean/internal/runner"
+	"github.com/FrangipaneTeam/bean/tui/pages/errorpanel"
+	"github.com/FrangipaneTeam/bean/tui/pages/k8s"
+)
+
+const timeRound = 100 * time.Millisecond
+
+var (
+	junitReport string
+	testNoDeps  bool
+
+	testCmd = &cobra.Command{
+		Use:   "test [directory|glob|example-id]...",
+		Short: "Run examples end-to-end against a cluster",
+		Long: `Apply each selected example with its dependencies, wait for its resources to be Ready and Synced,
+delete it and wait for its resources to be gone. Examples are selected by directory, glob or example-id.`,
+		Args:              cobra.MinimumNArgs(1),
+		SilenceUsage:      true,
+		ValidArgsFunction: completeExamples,
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			loaded, err := loadExamples()
+			if err != nil {
+				return err
+			}
+
+			selected, err := exlist.Select(loaded.Base, loaded.List(), args)
+			if err != nil {
+				return err
+			}
+
+			executor, err := k8s.NewExecutor(c)
+			if err != nil {
+				return fmt.Errorf("could not create kubernetes client: %w", err)
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			out := cmd.OutOrStdout()
+			opts := runner.Options{
+				Timeout:          c.WaitTimeout,
+				WithDependencies: !testNoDeps,
+			}
+
+			store := results.New(c)
+			runs := make([]runner.Result, 0, len(selected))
+			failed := 0
+			for _, e := range selected {
+				fmt.Fprintf(out, "▶ %s\n", e.FullPath)
+				res := runner.Run(ctx, executor, e, opts)
+				runs = append(runs, res)
+				if err = recordResult(store, res); err != nil {
+					return fmt.Errorf("could not record the result: %w", err)
+				}
+
+				if res.Passed() {
+					fmt.Fprintf(out, "  ✓ passed in %s\n", res.Duration.Round(timeRound))
+					continue
+				}
+				failed++
+				fmt.Fprintf(out, "  ✗ %s failed in %s: %s\n", res.Step, res.Duration.Round(timeRound), res.Err)
+
+				// the interrupted example was deleted, the next ones are not run
+				if ctx.Err() != nil {
+					break
+				}
+			}
+
+			fmt.Fprintf(out, "\n%d passed, %d failed\n", len(runs)-failed, failed)
+
+			if junitReport != "" {
+				if err = writeJUnit(junitReport, runs); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "JUnit report written to %s\n", junitReport)
+			}
+
+			if ctx.Err() != nil {
+				return fmt.Errorf("interrupted after %d example(s): %w", len(runs), ctx.Err())
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d example(s) failed", failed)
+			}
+			return nil
+		},
+	}
+)
+
+// loadExamples loads the examples of the provider path.
+func loadExamples() (exlist.LoadedExamples, error) {
+	switch msg := examples.GenerateExamplesList(c).(type) {
+	case exlist.LoadedExamples:
+		return msg, nil
+	case errorpanel.ErrorMsg:
+		return exlist.LoadedExamples{}, fmt.Errorf("%s: %w", msg.Reason, msg.Cause)
+	case *errorpanel.ErrorMsg:
+		return exlist.LoadedExamples{}, fmt.Errorf("%s: %w", msg.Reason, msg.Cause)
+	}
+
+	return exlist.LoadedExamples{}, errors.New("could not load examples")
+}
+
+// recordResult records the outcome of the readiness of the run and of its delete in the results store.
+func recordResult(store *results.Store, res runner.Result) error {
+	file := res.Example.FullPath
+	switch res.Step {
+	case runner.StepApply:
+		return store.Record(file, results.StepApply, res.Err)
+	case runner.StepReady:
+		return store.Record(file, results.StepReady, res.Err)
+	}
+
+	if err := store.Record(file, results.StepReady, nil); err != nil {
+		return err
+	}
+	// a failed wait for the deletion is a failed delete
+	return store.Record(file, results.StepDelete, res.Err)
+}
+
+func writeJUnit(file string, results []runner.Result) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return runner.WriteJUnit(f, "bean", results)
+}
@@ -1,13 +1,70 @@
 // Package config provides a simple way to load configuration files
 package config
 
-import "github.com/spf13/viper"
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// DefaultWaitTimeout is the time to wait for applied resources to be Ready and Synced.
+	DefaultWaitTimeout = 15 * time.Minute
+	// DefaultExtraSuffix is the suffix of the extra files of an example.
+	DefaultExtraSuffix = ".extra"
+	// DefaultSecretSuffix is the suffix of the secret files of an example.
+	DefaultSecretSuffix = ".secret"
+)
 
 // Provider is the configuration provider.
 type Provider struct {
-	Path       string
-	Version    string
-	NewVersion string
-	Debug      bool
-	Viper      *viper.Viper
+	Path        string
+	Version     string
+	NewVersion  string
+	Debug       bool
+	Kubeconfig  string
+	KubeContext string
+	WaitTimeout time.Duration
+	Viper       *viper.Viper
+	// Files are the config files merged into Viper, in merge order.
+	Files []*File
+	// Profile is the active profile of variables rendered in the examples, none if empty.
+	Profile string
+}
+
+// Sidecars are the suffixes of the files applied with an example.
+type Sidecars struct {
+	Extra  string
+	Secret string
+}
+
+// Sidecars returns the suffixes of the sidecar files set by the sidecars key of the config.
+func (p Provider) Sidecars() Sidecars {
+	s := Sidecars{Extra: DefaultExtraSuffix, Secret: DefaultSecretSuffix}
+	if p.Viper == nil {
+		return s
+	}
+	if extra := p.Viper.GetString("sidecars.extra"); extra != "" {
+		s.Extra = extra
+	}
+	if secret := p.Viper.GetString("sidecars.secret"); secret != "" {
+		s.Secret = secret
+	}
+	return s
+}
+
+// DisabledRules returns the lint rules disabled by the lint.disabled key.
+func (p Provider) DisabledRules() []string {
+	if p.Viper == nil {
+		return nil
+	}
+	return p.Viper.GetStringSlice("lint.disabled")
+}
+
+// ProviderVersion returns the version of the provider set by the providerVersion key.
+func (p Provider) ProviderVersion() string {
+	if p.Viper == nil {
+		return ""
+	}
+	return p.Viper.GetString("providerVersion")
 }
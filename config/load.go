@@ -0,0 +1,159 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the config file, in the home directory and at the provider path.
+const FileName = ".bean.yaml"
+
+// SourceDefault is the source of the values that are not set in a config file.
+const SourceDefault = "default"
+
+// File is a config file merged into the effective config.
+type File struct {
+	Path string
+	// Values are the values of the file, keyed by their dotted path.
+	Values map[string]interface{}
+
+	values map[string]interface{}
+}
+
+// Files returns the config files to merge, in merge order: the home config, the project config at path
+// and the config file given with --config. Missing home and project configs are skipped.
+func Files(path, explicit string) ([]*File, error) {
+	candidates := []string{}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, FileName))
+	}
+	candidates = append(candidates, filepath.Join(path, FileName))
+
+	files := []*File{}
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		abs, err := filepath.Abs(candidate)
+		if err != nil || seen[abs] {
+			continue
+		}
+		seen[abs] = true
+
+		f, err := ReadFile(candidate)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	if explicit != "" {
+		f, err := ReadFile(explicit)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	return files, nil
+}
+
+// ReadFile reads a config file.
+func ReadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	if err = yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+
+	f := &File{
+		Path:   path,
+		Values: map[string]interface{}{},
+		values: values,
+	}
+	flatten("", values, f.Values)
+	return f, nil
+}
+
+// Merge merges the files into v, each file overriding the previous ones.
+func Merge(v *viper.Viper, files []*File) error {
+	for _, f := range files {
+		// viper changes the case of the keys of the maps it merges, the profile variables keep theirs
+		if err := v.MergeConfigMap(copyMap(f.values)); err != nil {
+			return fmt.Errorf("%s: %w", f.Path, err)
+		}
+	}
+	return nil
+}
+
+// Setting is a value of the effective config.
+type Setting struct {
+	Key    string
+	Value  interface{}
+	Source string
+}
+
+// Effective returns the values set by the files and the defaults of the schema, sorted by key.
+func Effective(files []*File) []Setting {
+	settings := map[string]Setting{}
+	for key, field := range Schema {
+		if field.Default != nil {
+			settings[key] = Setting{Key: key, Value: field.Default, Source: SourceDefault}
+		}
+	}
+
+	for _, f := range files {
+		for key, value := range f.Values {
+			settings[key] = Setting{Key: key, Value: value, Source: f.Path}
+		}
+	}
+
+	list := make([]Setting, 0, len(settings))
+	for _, s := range settings {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list
+}
+
+// copyMap returns a copy of values and of the maps it holds.
+func copyMap(values map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		if m, ok := v.(map[string]interface{}); ok {
+			v = copyMap(m)
+		}
+		c[k] = v
+	}
+	return c
+}
+
+// flatten adds the leaves of values to flat, keyed by their dotted path. Lists are leaves.
+func flatten(prefix string, values map[string]interface{}, flat map[string]interface{}) {
+	for k, v := range values {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		if m, ok := v.(map[string]interface{}); ok && len(m) > 0 {
+			flatten(key, m, flat)
+			continue
+		}
+		flat[key] = v
+	}
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func writeConfig(t *testing.T, path, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMerge(t *testing.T) {
+	const (
+		home = `kube:
+  context: home
+  waitTimeout: 5m
+sidecars:
+  extra: .home
+theme:
+  accent: blue
+`
+		project = `kube:
+  context: project
+  waitTimeout: 10m
+sidecars:
+  extra: .project
+`
+		explicit = `kube:
+  context: explicit
+`
+	)
+	tests := []struct {
+		name     string
+		home     string
+		project  string
+		explicit string
+		want     map[string]string
+		// sources are the files of the values of Effective, home, project or explicit
+		sources map[string]string
+	}{
+		{
+			name: "home",
+			home: home,
+			want: map[string]string{"kube.context": "home", "kube.waittimeout": "5m", "sidecars.extra": ".home", "theme.accent": "blue"},
+			sources: map[string]string{
+				"kube.context": "home", "kube.waitTimeout": "home", "sidecars.extra": "home", "sidecars.secret": SourceDefault,
+			},
+		},
+		{
+			name:    "project over home",
+			home:    home,
+			project: project,
+			want:    map[string]string{"kube.context": "project", "kube.waittimeout": "10m", "sidecars.extra": ".project", "theme.accent": "blue"},
+			sources: map[string]string{
+				"kube.context": "project", "kube.waitTimeout": "project", "sidecars.extra": "project", "theme.accent": "home",
+			},
+		},
+		{
+			name:     "explicit over project",
+			home:     home,
+			project:  project,
+			explicit: explicit,
+			want:     map[string]string{"kube.context": "explicit", "kube.waittimeout": "10m", "sidecars.extra": ".project", "theme.accent": "blue"},
+			sources:  map[string]string{"kube.context": "explicit", "kube.waitTimeout": "project"},
+		},
+		{
+			name:     "explicit without home",
+			project:  project,
+			explicit: explicit,
+			want:     map[string]string{"kube.context": "explicit", "kube.waittimeout": "10m", "sidecars.extra": ".project", "theme.accent": ""},
+			sources:  map[string]string{"kube.context": "explicit", "sidecars.extra": "project"},
+		},
+		{
+			name: "none",
+			want: map[string]string{"kube.context": "", "sidecars.extra": ""},
+			sources: map[string]string{
+				"examples": SourceDefault, "kube.waitTimeout": SourceDefault, "sidecars.extra": SourceDefault,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			homeDir, path := t.TempDir(), t.TempDir()
+			t.Setenv("HOME", homeDir)
+			paths := map[string]string{SourceDefault: SourceDefault}
+			if tt.home != "" {
+				paths["home"] = writeConfig(t, filepath.Join(homeDir, FileName), tt.home)
+			}
+			if tt.project != "" {
+				paths["project"] = writeConfig(t, filepath.Join(path, FileName), tt.project)
+			}
+			explicitFile := ""
+			if tt.explicit != "" {
+				explicitFile = writeConfig(t, filepath.Join(t.TempDir(), "bean.yaml"), tt.explicit)
+				paths["explicit"] = explicitFile
+			}
+
+			files, err := Files(path, explicitFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v := viper.New()
+			if err = Merge(v, files); err != nil {
+				t.Fatal(err)
+			}
+			for key, want := range tt.want {
+				if got := v.GetString(key); got != want {
+					t.Errorf("%s = %q, want %q", key, got, want)
+				}
+			}
+
+			effective := map[string]string{}
+			for _, s := range Effective(files) {
+				effective[s.Key] = s.Source
+			}
+			for key, source := range tt.sources {
+				if got := effective[key]; got != paths[source] {
+					t.Errorf("source of %s = %s, want the %s config %s", key, got, source, paths[source])
+				}
+			}
+		})
+	}
+}
+
+func TestFilesErrors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := t.TempDir()
+	if _, err := Files(path, filepath.Join(path, "missing.yaml")); err == nil {
+		t.Error("Files() of a missing --config file did not fail")
+	}
+
+	writeConfig(t, filepath.Join(path, FileName), "kube: [")
+	if _, err := Files(path, ""); err == nil {
+		t.Error("Files() of an invalid project config did not fail")
+	}
+}
+
+func TestFilesHomeProject(t *testing.T) {
+	// the provider path is the home directory, its config is merged once
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeConfig(t, filepath.Join(home, FileName), "profile: dev\n")
+	files, err := Files(home, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("Files() = %d files, want 1", len(files))
+	}
+}
+
+func TestMergeProfileVars(t *testing.T) {
+	f, err := ReadFile(writeConfig(t, filepath.Join(t.TempDir(), FileName), "profiles:\n  dev:\n    REGION: us-west-1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := Provider{Files: []*File{f}, Profile: "dev", Viper: viper.New()}
+	if err = Merge(p.Viper, p.Files); err != nil {
+		t.Fatal(err)
+	}
+
+	vars, err := p.ProfileVars()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"REGION": "us-west-1"}; !reflect.DeepEqual(vars, want) {
+		t.Errorf("ProfileVars() after Merge() = %v, want %v", vars, want)
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Profiles returns the names of the profiles set by the profiles key of the config files, sorted.
+func (p Provider) Profiles() []string {
+	seen := map[string]bool{}
+	for _, f := range p.Files {
+		profiles, _ := f.values["profiles"].(map[string]interface{})
+		for name := range profiles {
+			seen[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProfileVars returns the variables of the active profile, merged from the config files.
+// The variable names keep their case, unlike the viper keys.
+func (p Provider) ProfileVars() (map[string]string, error) {
+	vars := map[string]string{}
+	if p.Profile == "" {
+		return vars, nil
+	}
+
+	found := false
+	for _, f := range p.Files {
+		profiles, _ := f.values["profiles"].(map[string]interface{})
+		values, ok := profiles[p.Profile].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		found = true
+		for k, v := range values {
+			vars[k] = fmt.Sprint(v)
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("unknown profile %s, expected one of %v", p.Profile, p.Profiles())
+	}
+	return vars, nil
+}
+
+// NextProfile returns the profile following the active one, no profile after the last one.
+func (p Provider) NextProfile() string {
+	profiles := p.Profiles()
+	for i, name := range profiles {
+		if name == p.Profile {
+			if i+1 < len(profiles) {
+				return profiles[i+1]
+			}
+			return ""
+		}
+	}
+	if len(profiles) > 0 {
+		return profiles[0]
+	}
+	return ""
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DefaultExamplesRoot is the examples directory used when the examples key is not set.
+	DefaultExamplesRoot = "examples"
+	// DefaultCRDsRoot is the CRDs directory used when the crds key is not set.
+	DefaultCRDsRoot = "package/crds"
+)
+
+// ExamplesRoots returns the examples directories set by the examples key of the config, paths or globs relative to the provider path.
+func (p Provider) ExamplesRoots() ([]string, error) {
+	return p.roots("examples", DefaultExamplesRoot)
+}
+
+// CRDsRoots returns the CRDs directories set by the crds key of the config, paths or globs relative to the provider path.
+func (p Provider) CRDsRoots() ([]string, error) {
+	return p.roots("crds", DefaultCRDsRoot)
+}
+
+// ExamplesExcluded returns true if the path or one of its directories is excluded by a ! pattern of the examples key.
+func (p Provider) ExamplesExcluded(path string) bool {
+	_, excludes := p.patterns("examples", DefaultExamplesRoot)
+	return isExcluded(excludes, path)
+}
+
+// roots expands the patterns of the key to the existing directories, without duplicates and without the
+// directories excluded by the patterns starting with !.
+func (p Provider) roots(key, defaultRoot string) ([]string, error) {
+	patterns, excludes := p.patterns(key, defaultRoot)
+	for _, pattern := range excludes {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid %s pattern !%s: %w", key, pattern, err)
+		}
+	}
+
+	roots := []string{}
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(p.Path, pattern)
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %s: %w", key, pattern, err)
+		}
+
+		for _, m := range matches {
+			if info, errStat := os.Stat(m); errStat != nil || !info.IsDir() || seen[m] || isExcluded(excludes, m) {
+				continue
+			}
+			seen[m] = true
+			roots = append(roots, m)
+		}
+	}
+
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no %s directory found in %s matching %s", key, p.Path, strings.Join(patterns, ", "))
+	}
+
+	return roots, nil
+}
+
+// patterns returns the patterns of the key and its exclude patterns, the latter without their ! and joined to the
+// provider path. The default root is included when the key only has exclude patterns.
+func (p Provider) patterns(key, defaultRoot string) (patterns, excludes []string) {
+	list := []string{defaultRoot}
+	if p.Viper != nil && p.Viper.IsSet(key) {
+		list = p.Viper.GetStringSlice(key)
+	}
+
+	for _, pattern := range list {
+		exclude, ok := strings.CutPrefix(pattern, "!")
+		if !ok {
+			patterns = append(patterns, pattern)
+			continue
+		}
+		if !filepath.IsAbs(exclude) {
+			exclude = filepath.Join(p.Path, exclude)
+		}
+		excludes = append(excludes, exclude)
+	}
+	if len(patterns) == 0 {
+		patterns = []string{defaultRoot}
+	}
+	return patterns, excludes
+}
+
+// isExcluded returns true if path or one of its parent directories matches one of the exclude patterns.
+func isExcluded(excludes []string, path string) bool {
+	if len(excludes) == 0 {
+		return false
+	}
+	for dir := filepath.Clean(path); ; dir = filepath.Dir(dir) {
+		for _, pattern := range excludes {
+			if ok, _ := filepath.Match(pattern, dir); ok {
+				return true
+			}
+		}
+		if filepath.Dir(dir) == dir {
+			return false
+		}
+	}
+}
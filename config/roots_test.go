@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// testProvider returns a provider with the directories created and the values set in its config.
+func testProvider(t *testing.T, dirs []string, values map[string]interface{}) Provider {
+	t.Helper()
+	p := Provider{Path: t.TempDir(), Viper: viper.New()}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(p.Path, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for key, value := range values {
+		p.Viper.Set(key, value)
+	}
+	return p
+}
+
+func TestRoots(t *testing.T) {
+	dirs := []string{
+		"examples/ec2/v1alpha1", "examples/ec2/v1beta1", "examples/s3/v1beta1", "examples-generated",
+		"package/crds", "apis/ec2/crds", "apis/s3/crds",
+	}
+	tests := []struct {
+		name    string
+		key     string
+		values  []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "examples default", key: "examples", want: []string{"examples"}},
+		{name: "crds default", key: "crds", want: []string{"package/crds"}},
+		{name: "paths", key: "examples", values: []string{"examples", "examples-generated"}, want: []string{"examples", "examples-generated"}},
+		{name: "globs", key: "crds", values: []string{"apis/*/crds"}, want: []string{"apis/ec2/crds", "apis/s3/crds"}},
+		{
+			name:   "files and duplicates skipped",
+			key:    "examples",
+			values: []string{"examples*", "examples"},
+			want:   []string{"examples", "examples-generated"},
+		},
+		{name: "excluded", key: "crds", values: []string{"apis/*/crds", "!apis/s3/*"}, want: []string{"apis/ec2/crds"}},
+		{
+			name:   "excluded subdirectories",
+			key:    "examples",
+			values: []string{"examples/*/*", "!examples/*/v1alpha1"},
+			want:   []string{"examples/ec2/v1beta1", "examples/s3/v1beta1"},
+		},
+		{name: "excluded parent", key: "examples", values: []string{"examples/*/*", "!examples/ec2"}, want: []string{"examples/s3/v1beta1"}},
+		{name: "only excludes", key: "examples", values: []string{"!examples-generated"}, want: []string{"examples"}},
+		{name: "not found", key: "examples", values: []string{"examples-missing"}, wantErr: true},
+		{name: "all excluded", key: "examples", values: []string{"examples", "!examples"}, wantErr: true},
+		{name: "invalid pattern", key: "examples", values: []string{"examples["}, wantErr: true},
+		{name: "invalid exclude", key: "examples", values: []string{"examples", "!examples["}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := map[string]interface{}{}
+			if tt.values != nil {
+				values[tt.key] = tt.values
+			}
+			p := testProvider(t, dirs, values)
+			if err := os.WriteFile(filepath.Join(p.Path, "examples.yaml"), nil, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			roots, err := p.ExamplesRoots()
+			if tt.key == "crds" {
+				roots, err = p.CRDsRoots()
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("roots() error = %v, want an error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := []string{}
+			for _, root := range roots {
+				rel, _ := filepath.Rel(p.Path, root)
+				got = append(got, filepath.ToSlash(rel))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("roots() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExamplesExcluded(t *testing.T) {
+	p := testProvider(t, nil, map[string]interface{}{
+		"examples": []string{"examples", "!examples/*/v1alpha1", "!examples/ec2/draft.yaml"},
+	})
+	tests := map[string]bool{
+		"examples":                         false,
+		"examples/ec2/v1beta1/vpc.yaml":    false,
+		"examples/ec2/v1alpha1":            true,
+		"examples/ec2/v1alpha1/vpc.yaml":   true,
+		"examples/ec2/v1alpha1/nested/vpc": true,
+		"examples/ec2/draft.yaml":          true,
+		"examples/ec2/draft.yaml.extra":    false,
+	}
+	for path, want := range tests {
+		if got := p.ExamplesExcluded(filepath.Join(p.Path, path)); got != want {
+			t.Errorf("ExamplesExcluded(%s) = %v, want %v", path, got, want)
+		}
+	}
+}
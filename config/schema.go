@@ -0,0 +1,174 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Type is the type of a config value.
+type Type string
+
+const (
+	// TypeString is a string.
+	TypeString Type = "string"
+	// TypeStrings is a list of strings, a single string is accepted.
+	TypeStrings Type = "list of strings"
+	// TypeDuration is a duration like 30s or 15m.
+	TypeDuration Type = "duration"
+	// TypeBool is a boolean.
+	TypeBool Type = "boolean"
+	// TypeObjects is a list of objects with the keys of the field.
+	TypeObjects Type = "list of objects"
+	// TypeScalar is a string, a number or a boolean.
+	TypeScalar Type = "scalar"
+)
+
+// Field is a key of the config.
+type Field struct {
+	Type        Type
+	Default     interface{}
+	Description string
+	// Keys are the keys of the objects of a TypeObjects field.
+	Keys map[string]Type
+}
+
+// Schema is the schema of the config, keyed by dotted path.
+// A key ending with .* matches all the keys below it.
+var Schema = map[string]Field{
+	"examples": {Type: TypeStrings, Default: []string{DefaultExamplesRoot}, Description: "examples directories, paths or globs relative to the provider path, excluded with a leading !"},
+	"crds":     {Type: TypeStrings, Default: []string{DefaultCRDsRoot}, Description: "CRDs directories, paths or globs relative to the provider path, excluded with a leading !"},
+
+	"sidecars.extra":  {Type: TypeString, Default: DefaultExtraSuffix, Description: "suffix of the extra files applied with an example"},
+	"sidecars.secret": {Type: TypeString, Default: DefaultSecretSuffix, Description: "suffix of the secret files applied with an example"},
+
+	"kube.kubeconfig":  {Type: TypeString, Description: "path to the kubeconfig file, overridden by --kubeconfig"},
+	"kube.context":     {Type: TypeString, Description: "the kubeconfig context to use, overridden by --context"},
+	"kube.waitTimeout": {Type: TypeDuration, Default: DefaultWaitTimeout.String(), Description: "time to wait for applied resources, overridden by --wait-timeout"},
+
+	"profile":    {Type: TypeString, Description: "the active profile, overridden by --profile"},
+	"profiles.*": {Type: TypeScalar, Description: "variables of the profiles, rendered in the examples as ${VAR}, or {{ .VAR }} in the files with a # bean:template line"},
+
+	"providerVersion": {Type: TypeString, Description: "version of the provider recorded with the run results, git describe of the provider path by default"},
+
+	"lint.disabled": {Type: TypeStrings, Description: "lint rules to disable, see bean lint --rules"},
+
+	"secrets.ageKeyFile": {Type: TypeString, Description: "age identities file decrypting the age and SOPS encrypted secret files"},
+
+	"simulator.latency": {Type: TypeDuration, Description: "time taken by each call to the simulated cluster"},
+	"simulator.synced":  {Type: TypeDuration, Description: "time after which an applied object becomes Synced"},
+	"simulator.ready":   {Type: TypeDuration, Description: "time after which an applied object becomes Ready"},
+	"simulator.deleted": {Type: TypeDuration, Description: "time after which a deleted object is gone"},
+	"simulator.failures": {Type: TypeObjects, Description: "failures injected by the simulated cluster", Keys: map[string]Type{
+		"kind":      TypeString,
+		"name":      TypeString,
+		"condition": TypeString,
+		"apply":     TypeBool,
+		"message":   TypeString,
+	}},
+
+	"theme.*": {Type: TypeString, Description: "colours and symbols of the theme"},
+}
+
+// Lookup returns the field of the key.
+func Lookup(key string) (Field, bool) {
+	key = strings.ToLower(key)
+	for k, field := range Schema {
+		k = strings.ToLower(k)
+		if k == key || (strings.HasSuffix(k, ".*") && strings.HasPrefix(key, strings.TrimSuffix(k, "*"))) {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+// Validate checks the values of the file against the schema.
+func (f *File) Validate() []error {
+	keys := make([]string, 0, len(f.Values))
+	for key := range f.Values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	errs := []error{}
+	for _, key := range keys {
+		field, ok := Lookup(key)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s: unknown key", f.Path, key))
+			continue
+		}
+		if err := checkType(field, f.Values[key]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", f.Path, key, err))
+		}
+	}
+	return errs
+}
+
+// checkType returns an error if the value does not have the type of the field.
+func checkType(field Field, value interface{}) error {
+	switch field.Type {
+	case TypeString:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("expected a %s, got %v", field.Type, value)
+		}
+
+	case TypeScalar:
+		switch value.(type) {
+		case string, int, float64, bool:
+		default:
+			return fmt.Errorf("expected a %s, got %v", field.Type, value)
+		}
+
+	case TypeBool:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("expected a %s, got %v", field.Type, value)
+		}
+
+	case TypeDuration:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a %s, got %v", field.Type, value)
+		}
+		if _, err := time.ParseDuration(s); err != nil {
+			return fmt.Errorf("expected a %s: %w", field.Type, err)
+		}
+
+	case TypeStrings:
+		if _, ok := value.(string); ok {
+			return nil
+		}
+		list, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected a %s, got %v", field.Type, value)
+		}
+		for i, item := range list {
+			if _, ok := item.(string); !ok {
+				return fmt.Errorf("[%d]: expected a string, got %v", i, item)
+			}
+		}
+
+	case TypeObjects:
+		list, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected a %s, got %v", field.Type, value)
+		}
+		for i, item := range list {
+			obj, ok := item.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("[%d]: expected an object, got %v", i, item)
+			}
+			for k, v := range obj {
+				t, known := field.Keys[k]
+				if !known {
+					return fmt.Errorf("[%d].%s: unknown key", i, k)
+				}
+				if err := checkType(Field{Type: t}, v); err != nil {
+					return fmt.Errorf("[%d].%s: %w", i, k, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
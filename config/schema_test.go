@@ -0,0 +1,112 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		// want are the errors, without the path of the file
+		want []string
+	}{
+		{
+			name: "valid",
+			content: `examples: [examples, examples-generated]
+crds: package/crds
+kube:
+  waitTimeout: 20m
+profiles:
+  dev:
+    REGION: us-west-1
+    COUNT: 2
+    PUBLIC: true
+simulator:
+  failures:
+    - kind: Subnet
+      apply: true
+      message: quota exceeded
+theme:
+  accent: "#ff0000"
+`,
+			want: []string{},
+		},
+		{
+			name:    "unknown keys",
+			content: "examples: [examples]\nkube:\n  namespace: default\nprofil: dev\n",
+			want:    []string{"kube.namespace: unknown key", "profil: unknown key"},
+		},
+		{
+			name:    "string",
+			content: "kube:\n  context: 3\n",
+			want:    []string{"kube.context: expected a string, got 3"},
+		},
+		{
+			name:    "duration",
+			content: "kube:\n  waitTimeout: 20\nsimulator:\n  ready: soon\n",
+			want: []string{
+				"kube.waitTimeout: expected a duration, got 20",
+				`simulator.ready: expected a duration: time: invalid duration "soon"`,
+			},
+		},
+		{
+			name:    "list of strings",
+			content: "examples: [examples, 2]\nlint:\n  disabled: true\n",
+			want:    []string{"examples: [1]: expected a string, got 2", "lint.disabled: expected a list of strings, got true"},
+		},
+		{
+			name:    "scalar",
+			content: "profiles:\n  dev:\n    ZONES: [a, b]\n",
+			want:    []string{"profiles.dev.ZONES: expected a scalar, got [a b]"},
+		},
+		{
+			name:    "list of objects",
+			content: "simulator:\n  failures:\n    - kind: Subnet\n      apply: yes please\n",
+			want:    []string{"simulator.failures: [0].apply: expected a boolean, got yes please"},
+		},
+		{
+			name:    "object",
+			content: "simulator:\n  failures:\n    - Subnet\n",
+			want:    []string{"simulator.failures: [0]: expected an object, got Subnet"},
+		},
+		{
+			name:    "unknown object key",
+			content: "simulator:\n  failures:\n    - delay: 1s\n",
+			want:    []string{"simulator.failures: [0].delay: unknown key"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ReadFile(writeConfig(t, filepath.Join(t.TempDir(), FileName), tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, err := range f.Validate() {
+				got = append(got, strings.TrimPrefix(err.Error(), f.Path+": "))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := map[string]bool{
+		"kube.waittimeout":    true,
+		"profiles.dev.REGION": true,
+		"theme.accent":        true,
+		"theme":               false,
+		"kube":                false,
+	}
+	for key, want := range tests {
+		if _, got := Lookup(key); got != want {
+			t.Errorf("Lookup(%s) = %v, want %v", key, got, want)
+		}
+	}
+}
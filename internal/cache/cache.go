@@ -0,0 +1,327 @@
+// Package cache keeps the parsed examples and CRDs of the providers under the user cache dir, so that
+// bean only parses the files changed since its previous run. A file is parsed again when its size or
+// modification time changes. The index of a provider is written through a temporary file renamed over
+// the previous one, so the TUI and the headless commands can share it: a reader always gets a whole
+// index, and a lost update only costs a parse on the next run.
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FrangipaneTeam/bean/internal/crd"
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+)
+
+const (
+	// Dir is the directory of bean in the user cache dir.
+	Dir = "bean"
+	// IndexDir is the directory of the indexes of the parsed files in Dir.
+	IndexDir = "index"
+
+	// version is the version of the cached structs, an index of another version is ignored.
+	version = 3
+)
+
+func init() {
+	// the types of the values of the specs and the enums of the schemas
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+	gob.Register(time.Time{})
+}
+
+// Index is the parsed files of a provider, keyed by file path.
+type Index struct {
+	Version      int
+	Provider     string
+	ExampleFiles map[string]ExampleFile
+	CRDFiles     map[string]CRDFile
+	// CRDsSum is the sum of the stamps of the CRD files the examples are checked against.
+	CRDsSum string
+
+	mu      sync.Mutex
+	path    string
+	changed bool
+	// used are the files read since the index was opened.
+	used map[string]bool
+}
+
+// Stamp is the size and modification time of a file, zero if it does not exist.
+type Stamp struct {
+	Size int64
+	// ModTime is the modification time in nanoseconds, compared as is.
+	ModTime int64
+}
+
+// ExampleFile is a parsed example file.
+type ExampleFile struct {
+	Stamp
+	// Extra and Secret are the sidecar files of the example.
+	Extra, Secret Sidecar
+	// Context is the sum of what else the parsing depends on, see Context.
+	Context string
+	// Example is the example as parsed and checked against the CRDs, nil if the file has no resource.
+	Example *exlist.Example
+}
+
+// Sidecar is a sidecar file of an example, its stamp is zero if it does not exist.
+type Sidecar struct {
+	Path string
+	Stamp
+}
+
+// CRDFile is a parsed CRD file.
+type CRDFile struct {
+	Stamp
+	CRD crd.CRD
+}
+
+// Open returns the index of the provider path, empty if it can't be read.
+func Open(providerPath string) *Index {
+	idx := &Index{used: map[string]bool{}}
+	abs, err := filepath.Abs(providerPath)
+	if err != nil {
+		abs = providerPath
+	}
+	if path, errPath := File(IndexDir, abs, ".gob"); errPath == nil {
+		idx.path = path
+		if f, errOpen := os.Open(path); errOpen == nil {
+			if gob.NewDecoder(f).Decode(idx) != nil || idx.Version != version || idx.Provider != abs {
+				idx.ExampleFiles, idx.CRDFiles, idx.CRDsSum = nil, nil, ""
+			}
+			f.Close()
+		}
+	}
+
+	idx.Version, idx.Provider = version, abs
+	if idx.ExampleFiles == nil {
+		idx.ExampleFiles = map[string]ExampleFile{}
+	}
+	if idx.CRDFiles == nil {
+		idx.CRDFiles = map[string]CRDFile{}
+	}
+	return idx
+}
+
+// File returns the file of a provider path in the dir of Dir in the user cache dir.
+func File(dir, providerPath, ext string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	key, err := Key(providerPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, Dir, dir, key+ext), nil
+}
+
+// Key returns the key of the files of a provider path, the sum of its absolute path.
+func Key(providerPath string) (string, error) {
+	abs, err := filepath.Abs(providerPath)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// NewStamp returns the stamp of a file, zero if it does not exist.
+func NewStamp(path string) Stamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Stamp{}
+	}
+	return Stamp{Size: info.Size(), ModTime: info.ModTime().UnixNano()}
+}
+
+// Context returns the sum of what the parsing of the examples depends on besides their files,
+// the profile variables, the sidecar suffixes and the CRDs for instance.
+func Context(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		fmt.Fprintf(h, "%d:%s", len(p), p)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// VarsContext returns the variables as a part of a context, sorted by name.
+func VarsContext(vars map[string]string) string {
+	pairs := make([]string, 0, len(vars))
+	for k, v := range vars {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\n")
+}
+
+// CRDs returns the CRDs of the files found in the paths, parsing the files changed since they were cached.
+// It sets the sum of the CRD files, the examples are checked against them.
+func (idx *Index) CRDs(paths ...string) ([]crd.CRD, error) {
+	files, err := crd.Files(paths...)
+	if err != nil {
+		return nil, err
+	}
+
+	crds := make([]crd.CRD, 0, len(files))
+	stamps := make([]string, 0, len(files))
+	for _, file := range files {
+		stamp := NewStamp(file)
+		idx.mu.Lock()
+		cached, ok := idx.CRDFiles[file]
+		idx.used[file] = true
+		idx.mu.Unlock()
+
+		if !ok || cached.Stamp != stamp || stamp == (Stamp{}) {
+			c, errRead := crd.ReadFile(file)
+			if errRead != nil {
+				return nil, errRead
+			}
+			cached = CRDFile{Stamp: stamp, CRD: c}
+			idx.mu.Lock()
+			idx.CRDFiles[file] = cached
+			idx.changed = true
+			idx.mu.Unlock()
+		}
+		crds = append(crds, cached.CRD)
+		stamps = append(stamps, fmt.Sprintf("%s %d %d", file, stamp.Size, stamp.ModTime))
+	}
+
+	idx.mu.Lock()
+	if sum := Context(stamps...); sum != idx.CRDsSum {
+		idx.CRDsSum, idx.changed = sum, true
+	}
+	idx.mu.Unlock()
+	return crds, nil
+}
+
+// Example returns a copy of the cached example of the path, if the file, its sidecars and the context
+// did not change since it was cached. The example is nil if the file has no resource.
+func (idx *Index) Example(path, extra, secret, contextSum string) (*exlist.Example, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.used[path] = true
+	cached, ok := idx.ExampleFiles[path]
+	if !ok || cached.Context != contextSum || cached.Extra != newSidecar(extra) || cached.Secret != newSidecar(secret) {
+		return nil, false
+	}
+	if stamp := NewStamp(path); stamp == (Stamp{}) || cached.Stamp != stamp {
+		return nil, false
+	}
+	return copyExample(cached.Example), true
+}
+
+// PutExample caches a copy of the example parsed from the files of f, nil if the file has no resource.
+func (idx *Index) PutExample(path, contextSum string, f ExampleFile, e *exlist.Example) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.used[path] = true
+	f.Context, f.Example = contextSum, copyExample(e)
+	idx.ExampleFiles[path] = f
+	idx.changed = true
+}
+
+// Stamps returns the stamps of an example file and its sidecars, to cache the example parsed after.
+// They are taken before the files are read, a file changed while it is read is parsed again next time.
+func Stamps(path, extra, secret string) ExampleFile {
+	return ExampleFile{Stamp: NewStamp(path), Extra: newSidecar(extra), Secret: newSidecar(secret)}
+}
+
+// Stale returns true if the example file or its sidecars changed since they were cached.
+func (f ExampleFile) Stale(path string) bool {
+	return f.Stamp != NewStamp(path) || f.Extra != newSidecar(f.Extra.Path) || f.Secret != newSidecar(f.Secret.Path)
+}
+
+func newSidecar(path string) Sidecar {
+	return Sidecar{Path: path, Stamp: NewStamp(path)}
+}
+
+// Prune removes the files that were not read since the index was opened, the files that no longer exist.
+func (idx *Index) Prune() {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for path := range idx.ExampleFiles {
+		if !idx.used[path] {
+			delete(idx.ExampleFiles, path)
+			idx.changed = true
+		}
+	}
+	for path := range idx.CRDFiles {
+		if !idx.used[path] {
+			delete(idx.CRDFiles, path)
+			idx.changed = true
+		}
+	}
+}
+
+// Save writes the index if a file was parsed since it was opened.
+func (idx *Index) Save() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if !idx.changed {
+		return nil
+	}
+	if idx.path == "" {
+		return errors.New("no cache path")
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(idx); err != nil {
+		return err
+	}
+	if err := WriteFile(idx.path, buf.Bytes()); err != nil {
+		return err
+	}
+	idx.changed = false
+	return nil
+}
+
+// WriteFile writes the data through a temporary file renamed over the file, so a reader gets a whole file.
+func WriteFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// copyExample returns a copy of the parsed fields of the example, without the state set after the loading:
+// the dependencies, the readiness and the last run. The diagnostics are copied, the conventions are added to them.
+func copyExample(e *exlist.Example) *exlist.Example {
+	if e == nil {
+		return nil
+	}
+	c := *e
+	c.Diagnostics = append(c.Diagnostics[:0:0], e.Diagnostics...)
+	c.DependenciesFiles = map[string]bool{}
+	c.DependenciesLayers = nil
+	c.DependenciesErr = nil
+	c.DependenciesWarning = ""
+	c.Readiness, c.ReadinessFailed, c.LastRun = "", false, ""
+	return &c
+}
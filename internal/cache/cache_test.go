@@ -0,0 +1,215 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+)
+
+const vpcCRD = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: ec2.aws.upbound.io
+  names:
+    kind: VPC
+  versions:
+  - name: v1beta1
+`
+
+// writeFile writes the file and returns its path.
+func writeFile(t *testing.T, path, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// testIndex returns the index of a new provider path, in a temporary cache dir.
+func testIndex(t *testing.T) (*Index, string) {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	return Open(dir), dir
+}
+
+// put caches the example of the file as the loading does.
+func put(idx *Index, path, extra, secret, context string) {
+	idx.PutExample(path, context, Stamps(path, extra, secret), &exlist.Example{FullPath: path})
+}
+
+func TestExample(t *testing.T) {
+	vars := Context(VarsContext(map[string]string{"region": "us-west-1"}))
+	tests := []struct {
+		name    string
+		change  func(t *testing.T, path, extra string)
+		context string
+		hit     bool
+	}{
+		{
+			name:    "unchanged",
+			change:  func(t *testing.T, path, extra string) {},
+			context: vars,
+			hit:     true,
+		},
+		{
+			name: "file edited",
+			change: func(t *testing.T, path, extra string) {
+				writeFile(t, path, "kind: VPC\nmetadata:\n  name: other\n")
+			},
+			context: vars,
+		},
+		{
+			name: "file touched",
+			change: func(t *testing.T, path, extra string) {
+				later := time.Now().Add(time.Hour)
+				if err := os.Chtimes(path, later, later); err != nil {
+					t.Fatal(err)
+				}
+			},
+			context: vars,
+		},
+		{
+			name:    "file deleted",
+			change:  func(t *testing.T, path, extra string) { os.Remove(path) },
+			context: vars,
+		},
+		{
+			name:    "sidecar added",
+			change:  func(t *testing.T, path, extra string) { writeFile(t, extra, "kind: Secret\n") },
+			context: vars,
+		},
+		{
+			name:    "profile variable changed",
+			change:  func(t *testing.T, path, extra string) {},
+			context: Context(VarsContext(map[string]string{"region": "eu-west-3"})),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx, dir := testIndex(t)
+			path := writeFile(t, filepath.Join(dir, "vpc.yaml"), "kind: VPC\nmetadata:\n  name: vpc\n")
+			extra := filepath.Join(dir, "vpc.yaml.extra")
+			put(idx, path, extra, "", vars)
+
+			tt.change(t, path, extra)
+			e, hit := idx.Example(path, extra, "", tt.context)
+			if hit != tt.hit {
+				t.Fatalf("Example() hit = %v, want %v", hit, tt.hit)
+			}
+			if hit && e.FullPath != path {
+				t.Errorf("Example() = %s, want %s", e.FullPath, path)
+			}
+		})
+	}
+}
+
+func TestContext(t *testing.T) {
+	a := VarsContext(map[string]string{"region": "us-west-1", "zone": "a"})
+	if b := VarsContext(map[string]string{"zone": "a", "region": "us-west-1"}); a != b {
+		t.Errorf("VarsContext() depends on the order of the variables: %q, %q", a, b)
+	}
+	if Context("ab", "c") == Context("a", "bc") {
+		t.Error("Context() does not separate its parts")
+	}
+	if Context(a, ".extra") == Context(a, ".extra.yaml") {
+		t.Error("Context() ignores the sidecar suffixes")
+	}
+}
+
+func TestCRDs(t *testing.T) {
+	idx, dir := testIndex(t)
+	crds := filepath.Join(dir, "package", "crds")
+	file := writeFile(t, filepath.Join(crds, "ec2.aws.upbound.io_vpcs.yaml"), vpcCRD)
+
+	if _, err := idx.CRDs(crds); err != nil {
+		t.Fatal(err)
+	}
+	sum := idx.CRDsSum
+
+	writeFile(t, file, vpcCRD+"  - name: v1beta2\n")
+	got, err := idx.CRDs(crds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"v1beta1", "v1beta2"}; len(got) != 1 || !reflect.DeepEqual(got[0].VersionNames(), want) {
+		t.Errorf("CRDs() after an edit = %v, want the versions %v", got, want)
+	}
+	if idx.CRDsSum == sum {
+		t.Error("CRDs() did not change the sum of the edited CRDs")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	idx, dir := testIndex(t)
+	vpc := writeFile(t, filepath.Join(dir, "vpc.yaml"), "kind: VPC\n")
+	subnet := writeFile(t, filepath.Join(dir, "subnet.yaml"), "kind: Subnet\n")
+	put(idx, vpc, "", "", "")
+	put(idx, subnet, "", "", "")
+	if err := idx.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the subnet is deleted, the next loading only reads the vpc
+	os.Remove(subnet)
+	idx = Open(dir)
+	if _, hit := idx.Example(vpc, "", "", ""); !hit {
+		t.Fatal("Example() of the saved index missed")
+	}
+	idx.Prune()
+	if err := idx.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	paths := []string{}
+	for path := range Open(dir).ExampleFiles {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	if want := []string{vpc}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("Prune() kept %v, want %v", paths, want)
+	}
+}
+
+func TestOpenInvalid(t *testing.T) {
+	idx, dir := testIndex(t)
+	put(idx, writeFile(t, filepath.Join(dir, "vpc.yaml"), "kind: VPC\n"), "", "", "")
+	if err := idx.Save(); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, idx.path, "not an index")
+
+	if files := Open(dir).ExampleFiles; len(files) != 0 {
+		t.Errorf("Open() of an invalid index = %v, want an empty index", files)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "index", "provider.gob")
+	for _, data := range []string{"first", "second"} {
+		if err := WriteFile(path, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "second" {
+		t.Errorf("WriteFile() = %q, want the last data", got)
+	}
+	// the temporary files are renamed
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("WriteFile() left %d files, want 1", len(entries))
+	}
+}
@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Stats is the state of the cache of a provider.
+type Stats struct {
+	// Dir is the cache of bean, with the indexes of all the providers.
+	Dir   string    `json:"dir"`
+	Index FileStats `json:"index"`
+	// Examples and CRDs are the files of the index, the stale ones changed since they were cached.
+	Examples      int `json:"examples"`
+	StaleExamples int `json:"staleExamples"`
+	CRDs          int `json:"crds"`
+	StaleCRDs     int `json:"staleCrds"`
+	// Files and Size are the indexes of all the providers.
+	Files int   `json:"files"`
+	Size  int64 `json:"size"`
+}
+
+// FileStats is an index file, its size is 0 if it does not exist.
+type FileStats struct {
+	Path    string     `json:"path"`
+	Size    int64      `json:"size"`
+	ModTime *time.Time `json:"modTime,omitempty"`
+}
+
+// ReadStats returns the state of the cache of the provider path.
+func ReadStats(providerPath string) (Stats, error) {
+	s := Stats{}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return s, err
+	}
+	s.Dir = filepath.Join(cacheDir, Dir)
+
+	if s.Index, err = fileStats(IndexDir, providerPath, ".gob"); err != nil {
+		return s, err
+	}
+
+	if s.Index.Size > 0 {
+		idx := Open(providerPath)
+		s.Examples, s.CRDs = len(idx.ExampleFiles), len(idx.CRDFiles)
+		for path, f := range idx.ExampleFiles {
+			if f.Stale(path) {
+				s.StaleExamples++
+			}
+		}
+		for path, f := range idx.CRDFiles {
+			if f.Stamp != NewStamp(path) {
+				s.StaleCRDs++
+			}
+		}
+	}
+
+	err = filepath.WalkDir(s.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		s.Files++
+		s.Size += info.Size()
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		err = nil
+	}
+	return s, err
+}
+
+// Clear removes the index of the provider path, or the cache of all the providers.
+// It returns the file or the directory removed.
+func Clear(providerPath string, all bool) ([]string, error) {
+	if all {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		dir := filepath.Join(cacheDir, Dir)
+		if _, err = os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return []string{dir}, os.RemoveAll(dir)
+	}
+
+	path, err := File(IndexDir, providerPath, ".gob")
+	if err != nil {
+		return nil, err
+	}
+	err = os.Remove(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	return []string{path}, nil
+}
+
+func fileStats(dir, providerPath, ext string) (FileStats, error) {
+	path, err := File(dir, providerPath, ext)
+	if err != nil {
+		return FileStats{}, err
+	}
+	stats := FileStats{Path: path}
+	if info, errStat := os.Stat(path); errStat == nil {
+		modTime := info.ModTime()
+		stats.Size, stats.ModTime = info.Size(), &modTime
+	}
+	return stats, nil
+}
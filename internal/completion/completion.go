@@ -0,0 +1,104 @@
+// Package completion provides the candidates of the shell completion: the examples and the CRD kinds of a provider,
+// as loaded by the list of the examples.
+package completion
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/FrangipaneTeam/bean/internal/crd"
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+)
+
+// ExampleCandidates returns the example paths, as given and relative to the base of the list,
+// the example-ids, as annotated or kind.apiVersion, and the directories starting with prefix.
+// Each one is described by its kinds, its example or its number of examples, as "candidate\tdescription".
+func ExampleCandidates(loaded exlist.LoadedExamples, prefix string) []string {
+	candidates := map[string]string{}
+	ids := map[string][]string{}
+	dirs := map[string]int{}
+	for _, e := range loaded.List() {
+		desc := strings.Join(e.Kinds(), ", ")
+		rel := exlist.RelativeName(loaded.Base)(e.FullPath)
+		candidates[e.FullPath] = desc
+		candidates[rel] = desc
+
+		exampleIDs := []string{e.ExampleID}
+		for _, r := range e.Resources {
+			exampleIDs = append(exampleIDs, r.Metadata.Annotations.MetaUpboundIoExampleID)
+		}
+		for _, id := range exampleIDs {
+			if id != "" && !contains(ids[id], rel) {
+				ids[id] = append(ids[id], rel)
+			}
+		}
+
+		for dir := filepath.Dir(rel); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+			dirs[dir]++
+		}
+	}
+	for id, examples := range ids {
+		if len(examples) == 1 {
+			candidates[id] = examples[0]
+			continue
+		}
+		candidates[id] = plural(len(examples), "example")
+	}
+	for dir, count := range dirs {
+		if _, ok := candidates[dir]; !ok {
+			candidates[dir] = plural(count, "example")
+		}
+	}
+	return filter(candidates, prefix)
+}
+
+// KindCandidates returns the kinds and the group/kind of the CRDs starting with prefix, as "candidate\tdescription".
+func KindCandidates(crds []crd.CRD, prefix string) []string {
+	candidates := map[string]string{}
+	for _, c := range crds {
+		if c.Spec.Group == "" || c.Spec.Names.Kind == "" {
+			continue
+		}
+		candidates[c.Spec.Names.Kind] = c.Spec.Group
+		candidates[c.Spec.Group+"/"+c.Spec.Names.Kind] = ""
+	}
+	return filter(candidates, prefix)
+}
+
+// filter returns the candidates starting with prefix, sorted, with their description.
+func filter(candidates map[string]string, prefix string) []string {
+	keys := make([]string, 0, len(candidates))
+	for k := range candidates {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if d := candidates[k]; d != "" {
+			k += "\t" + d
+		}
+		out = append(out, k)
+	}
+	return out
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return "1 " + word
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package completion
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+
+	"github.com/FrangipaneTeam/bean/internal/crd"
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+)
+
+// newExample returns an example of a resource of the kind, annotated with the example-id if set.
+// Its ExampleID is kind.apiVersion, as the list sets it.
+func newExample(path, kind, exampleID string) *exlist.Example {
+	r := exlist.Resource{APIVersion: "ec2.aws.upbound.io/v1beta1", Kind: kind}
+	r.Metadata.Annotations.MetaUpboundIoExampleID = exampleID
+	return &exlist.Example{
+		FullPath:  path,
+		ExampleID: strings.ToLower(r.Kind + "." + r.APIVersion),
+		Resource:  r,
+		Resources: []exlist.Resource{r},
+	}
+}
+
+func TestExampleCandidates(t *testing.T) {
+	loaded := exlist.LoadedExamples{
+		Base: "/provider/examples",
+		Examples: map[string][]list.Item{exlist.RootDir: {
+			newExample("/provider/examples/ec2/vpc.yaml", "VPC", "ec2/v1beta1/vpc"),
+			newExample("/provider/examples/ec2/vpc2.yml", "VPC", ""),
+			newExample("/provider/examples/ec2/subnet.yaml", "Subnet", "ec2/v1beta1/subnet"),
+		}},
+	}
+
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"ec2/", []string{
+			"ec2/subnet.yaml\tSubnet",
+			"ec2/v1beta1/subnet\tec2/subnet.yaml",
+			"ec2/v1beta1/vpc\tec2/vpc.yaml",
+			"ec2/vpc.yaml\tVPC",
+			"ec2/vpc2.yml\tVPC",
+		}},
+		{"ec2", []string{
+			"ec2\t3 examples",
+			"ec2/subnet.yaml\tSubnet",
+			"ec2/v1beta1/subnet\tec2/subnet.yaml",
+			"ec2/v1beta1/vpc\tec2/vpc.yaml",
+			"ec2/vpc.yaml\tVPC",
+			"ec2/vpc2.yml\tVPC",
+		}},
+		{"subnet.", []string{"subnet.ec2.aws.upbound.io/v1beta1\tec2/subnet.yaml"}},
+		{"vpc.", []string{"vpc.ec2.aws.upbound.io/v1beta1\t2 examples"}},
+		{"/provider/examples/ec2/s", []string{"/provider/examples/ec2/subnet.yaml\tSubnet"}},
+	}
+	for _, tt := range tests {
+		if got := ExampleCandidates(loaded, tt.prefix); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ExampleCandidates(%q) = %q, want %q", tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestKindCandidates(t *testing.T) {
+	crds := []crd.CRD{{}, {}}
+	crds[0].Spec.Group, crds[0].Spec.Names.Kind = "ec2.aws.upbound.io", "VPC"
+	crds[1].Spec.Group, crds[1].Spec.Names.Kind = "ec2.aws.upbound.io", "VPCEndpoint"
+
+	want := []string{"VPC\tec2.aws.upbound.io", "VPCEndpoint\tec2.aws.upbound.io"}
+	if got := KindCandidates(crds, "V"); !reflect.DeepEqual(got, want) {
+		t.Errorf("KindCandidates() = %q, want %q", got, want)
+	}
+	want = []string{"ec2.aws.upbound.io/VPC", "ec2.aws.upbound.io/VPCEndpoint"}
+	if got := KindCandidates(crds, "ec2"); !reflect.DeepEqual(got, want) {
+		t.Errorf("KindCandidates() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,133 @@
+package coverage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BaselineFile is the default baseline of the coverage check, in the provider path.
+const BaselineFile = ".bean-coverage.yaml"
+
+// Baseline is the committed state of the coverage the check compares the report with.
+// The kinds are named group/kind.
+type Baseline struct {
+	// Tested are the kinds applied by an example.
+	Tested []string `json:"tested" yaml:"tested"`
+	// Untested are the kinds known to have no example, they don't fail the check.
+	Untested []string `json:"untested" yaml:"untested"`
+	// MinCoverage is the minimum percentage of tested kinds of each group.
+	MinCoverage map[string]float64 `json:"minCoverage,omitempty" yaml:"minCoverage,omitempty"`
+}
+
+// Problem is a kind or a group failing the check.
+type Problem struct {
+	// Kind is the group/kind of a kind, or the name of a group below its minimum coverage.
+	Kind    string
+	Message string
+}
+
+// String returns the problem as kind: message.
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Kind, p.Message)
+}
+
+// ReadBaseline reads the baseline file, an empty baseline if it does not exist.
+func ReadBaseline(path string) (Baseline, error) {
+	var b Baseline
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return b, err
+	}
+	if err = yaml.Unmarshal(data, &b); err != nil {
+		return b, fmt.Errorf("%s: %w", path, err)
+	}
+	return b, nil
+}
+
+// WriteBaseline writes the baseline file.
+func WriteBaseline(path string, b Baseline) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(b); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// NewBaseline returns the baseline of the report, with the minimum coverages of min.
+func NewBaseline(r Report, min map[string]float64) Baseline {
+	b := Baseline{Tested: []string{}, Untested: []string{}, MinCoverage: min}
+	for _, k := range r.Kinds {
+		if k.Tested {
+			b.Tested = append(b.Tested, k.Name())
+		} else {
+			b.Untested = append(b.Untested, k.Name())
+		}
+	}
+	return b
+}
+
+// Check returns the regressions of the report from the baseline: the tested kinds that became untested,
+// the new kinds without example and the groups below their minimum coverage. It also returns the known
+// untested kinds that are now tested, to be removed from the baseline.
+func (b Baseline) Check(r Report) (regressions, fixed []Problem) {
+	tested, untested := set(b.Tested), set(b.Untested)
+
+	for _, k := range r.Kinds {
+		name := k.Name()
+		switch {
+		case k.Tested && untested[name]:
+			fixed = append(fixed, Problem{Kind: name, Message: "is now tested, remove it from the untested kinds of the baseline"})
+		case k.Tested, untested[name]:
+		case tested[name]:
+			regressions = append(regressions, Problem{Kind: name, Message: "became untested, no example applies it anymore"})
+		default:
+			regressions = append(regressions, Problem{Kind: name, Message: "is a new kind without example"})
+		}
+	}
+
+	groups := map[string]Group{}
+	for _, g := range r.Groups() {
+		groups[g.Name] = g
+	}
+	names := make([]string, 0, len(b.MinCoverage))
+	for name := range b.MinCoverage {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g, ok := groups[name]
+		if !ok {
+			continue
+		}
+		if percent := g.Percent(); percent < b.MinCoverage[name] {
+			regressions = append(regressions, Problem{
+				Kind:    name,
+				Message: fmt.Sprintf("%.1f%% of the kinds are tested, below the minimum of %.1f%%", percent, b.MinCoverage[name]),
+			})
+		}
+	}
+	return regressions, fixed
+}
+
+// Name returns the kind as group/kind.
+func (k Kind) Name() string {
+	return k.Group + "/" + k.Kind
+}
+
+func set(names []string) map[string]bool {
+	s := make(map[string]bool, len(names))
+	for _, n := range names {
+		s[n] = true
+	}
+	return s
+}
@@ -0,0 +1,97 @@
+package coverage
+
+import (
+	"reflect"
+	"testing"
+)
+
+// report returns the report of the kinds of the group ec2, tested if in tested.
+func report(kinds []string, tested ...string) Report {
+	r := Report{}
+	for _, k := range kinds {
+		r.Kinds = append(r.Kinds, Kind{Group: "ec2", Kind: k, Tested: contains(tested, k)})
+	}
+	return r
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestBaselineCheck(t *testing.T) {
+	kinds := []string{"Route", "Subnet", "VPC"}
+	tests := []struct {
+		name            string
+		baseline        Baseline
+		report          Report
+		wantRegressions []Problem
+		wantFixed       []Problem
+	}{
+		{
+			name:     "unchanged",
+			baseline: Baseline{Tested: []string{"ec2/VPC"}, Untested: []string{"ec2/Route", "ec2/Subnet"}},
+			report:   report(kinds, "VPC"),
+		},
+		{
+			name:     "became untested",
+			baseline: Baseline{Tested: []string{"ec2/Subnet", "ec2/VPC"}, Untested: []string{"ec2/Route"}},
+			report:   report(kinds, "VPC"),
+			wantRegressions: []Problem{
+				{Kind: "ec2/Subnet", Message: "became untested, no example applies it anymore"},
+			},
+		},
+		{
+			name:     "new kind without example",
+			baseline: Baseline{Tested: []string{"ec2/VPC"}, Untested: []string{"ec2/Subnet"}},
+			report:   report(kinds, "VPC"),
+			wantRegressions: []Problem{
+				{Kind: "ec2/Route", Message: "is a new kind without example"},
+			},
+		},
+		{
+			name:     "untested kind now tested",
+			baseline: Baseline{Tested: []string{"ec2/VPC"}, Untested: []string{"ec2/Route", "ec2/Subnet"}},
+			report:   report(kinds, "Subnet", "VPC"),
+			wantFixed: []Problem{
+				{Kind: "ec2/Subnet", Message: "is now tested, remove it from the untested kinds of the baseline"},
+			},
+		},
+		{
+			name: "minimum coverage",
+			baseline: Baseline{
+				Tested:      []string{"ec2/VPC"},
+				Untested:    []string{"ec2/Route", "ec2/Subnet"},
+				MinCoverage: map[string]float64{"ec2": 50, "s3": 100},
+			},
+			report: report(kinds, "VPC"),
+			wantRegressions: []Problem{
+				{Kind: "ec2", Message: "33.3% of the kinds are tested, below the minimum of 50.0%"},
+			},
+		},
+		{
+			name: "minimum coverage reached",
+			baseline: Baseline{
+				Tested:      []string{"ec2/Subnet", "ec2/VPC"},
+				Untested:    []string{"ec2/Route"},
+				MinCoverage: map[string]float64{"ec2": 50},
+			},
+			report: report(kinds, "Subnet", "VPC"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regressions, fixed := tt.baseline.Check(tt.report)
+			if !reflect.DeepEqual(regressions, tt.wantRegressions) {
+				t.Errorf("Check() regressions = %v, want %v", regressions, tt.wantRegressions)
+			}
+			if !reflect.DeepEqual(fixed, tt.wantFixed) {
+				t.Errorf("Check() fixed = %v, want %v", fixed, tt.wantFixed)
+			}
+		})
+	}
+}
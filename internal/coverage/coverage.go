@@ -0,0 +1,97 @@
+// Package coverage provides the report of the CRDs covered by the examples and its output formats.
+package coverage
+
+import "github.com/FrangipaneTeam/bean/internal/results"
+
+// Report is the coverage of the CRDs of a provider by its examples.
+// Its JSON and YAML forms are a stable schema consumed by other tools.
+type Report struct {
+	Total  int `json:"total" yaml:"total"`
+	Tested int `json:"tested" yaml:"tested"`
+	// Passed and Failed are the number of tested kinds whose last run passed or failed.
+	Passed int    `json:"passed" yaml:"passed"`
+	Failed int    `json:"failed" yaml:"failed"`
+	Kinds  []Kind `json:"kinds" yaml:"kinds"`
+}
+
+// Kind is the coverage of a kind of CRD.
+type Kind struct {
+	Group    string   `json:"group" yaml:"group"`
+	Kind     string   `json:"kind" yaml:"kind"`
+	Versions []string `json:"versions" yaml:"versions"`
+	// Tested is true if an example applies the kind.
+	Tested bool `json:"tested" yaml:"tested"`
+	// Status is the state of the kind: no example, an example never run, or the last run passed or failed.
+	Status results.Status `json:"status" yaml:"status"`
+	// LastRun is the last run of the examples of the kind, nil if they never ran.
+	LastRun *results.Outcome `json:"lastRun,omitempty" yaml:"lastRun,omitempty"`
+	// Examples are the example files applying the kind, relative to the provider path.
+	Examples []string `json:"examples" yaml:"examples"`
+	// VersionCoverage are the versions of the CRD and the examples applying each of them.
+	VersionCoverage []Version `json:"versionCoverage" yaml:"versionCoverage"`
+	// DeprecatedOnly is true if the examples only apply deprecated versions of the kind.
+	DeprecatedOnly bool   `json:"deprecatedOnly" yaml:"deprecatedOnly"`
+	Fields         Fields `json:"fields" yaml:"fields"`
+}
+
+// Version is the coverage of a version of a kind.
+type Version struct {
+	Name       string `json:"name" yaml:"name"`
+	Storage    bool   `json:"storage" yaml:"storage"`
+	Deprecated bool   `json:"deprecated" yaml:"deprecated"`
+	Tested     bool   `json:"tested" yaml:"tested"`
+	// Examples are the example files applying the version, relative to the provider path.
+	Examples []string `json:"examples" yaml:"examples"`
+}
+
+// TestedVersions returns the names of the versions applied by an example.
+func (k Kind) TestedVersions() []string {
+	names := []string{}
+	for _, v := range k.VersionCoverage {
+		if v.Tested {
+			names = append(names, v.Name)
+		}
+	}
+	return names
+}
+
+// Group is the coverage of the kinds of a group.
+type Group struct {
+	Name  string
+	Kinds []Kind
+}
+
+// Groups returns the kinds grouped by group, in the order of the report.
+func (r Report) Groups() []Group {
+	groups := []Group{}
+	for _, k := range r.Kinds {
+		if len(groups) == 0 || groups[len(groups)-1].Name != k.Group {
+			groups = append(groups, Group{Name: k.Group})
+		}
+		g := &groups[len(groups)-1]
+		g.Kinds = append(g.Kinds, k)
+	}
+	return groups
+}
+
+// Percent returns the percentage of tested kinds.
+func (r Report) Percent() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(r.Tested) * 100 / float64(r.Total)
+}
+
+// Percent returns the percentage of tested kinds of the group.
+func (g Group) Percent() float64 {
+	if len(g.Kinds) == 0 {
+		return 0
+	}
+	tested := 0
+	for _, k := range g.Kinds {
+		if k.Tested {
+			tested++
+		}
+	}
+	return float64(tested) * 100 / float64(len(g.Kinds))
+}
@@ -0,0 +1,94 @@
+package coverage
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/FrangipaneTeam/bean/internal/crd"
+)
+
+// ProviderFields are the parts of the spec whose fields are covered. They share their fields, initProvider
+// only dropping the identifiers, so a field is covered if it is set in any of them.
+var ProviderFields = []string{"forProvider", "initProvider"}
+
+// Fields is the coverage of the fields of a kind.
+type Fields struct {
+	Total   int     `json:"total" yaml:"total"`
+	Tested  int     `json:"tested" yaml:"tested"`
+	Percent float64 `json:"percent" yaml:"percent"`
+	// Untested are the paths of the fields no example sets, relative to forProvider and initProvider.
+	Untested []string `json:"untested" yaml:"untested"`
+	// UntestedRequired are the untested fields required by the schema.
+	UntestedRequired []string `json:"untestedRequired" yaml:"untestedRequired"`
+}
+
+// UsedFields adds the paths of the fields set in the forProvider and initProvider of the spec to used.
+func UsedFields(spec map[string]interface{}, used map[string]bool) {
+	for _, name := range ProviderFields {
+		walk(spec[name], "", used)
+	}
+}
+
+func walk(v interface{}, path string, used map[string]bool) {
+	if path != "" {
+		used[path] = true
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			walk(child, join(path, k), used)
+		}
+	case []interface{}:
+		for _, child := range v {
+			walk(child, path+"[]", used)
+		}
+	}
+}
+
+// NewFields returns the coverage of the forProvider and initProvider fields of the spec schema
+// by the used paths. The coverage is empty if the CRD has no schema.
+func NewFields(spec *crd.Schema, used map[string]bool) Fields {
+	f := Fields{Untested: []string{}, UntestedRequired: []string{}}
+	if spec == nil {
+		return f
+	}
+
+	required := map[string]bool{}
+	for _, field := range spec.Fields("") {
+		for _, name := range ProviderFields {
+			if rel, ok := strings.CutPrefix(field.Path, name+"."); ok {
+				required[rel] = required[rel] || field.Required
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(required))
+	for path := range required {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		f.Total++
+		if used[path] {
+			f.Tested++
+			continue
+		}
+		f.Untested = append(f.Untested, path)
+		if required[path] {
+			f.UntestedRequired = append(f.UntestedRequired, path)
+		}
+	}
+	if f.Total > 0 {
+		f.Percent = math.Round(float64(f.Tested)*1000/float64(f.Total)) / 10
+	}
+	return f
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
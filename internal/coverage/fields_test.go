@@ -0,0 +1,33 @@
+package coverage
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/FrangipaneTeam/bean/internal/crd"
+)
+
+func TestNewFields(t *testing.T) {
+	c, err := crd.ReadFile(filepath.Join("..", "crd", "testdata", "ec2.aws.upbound.io_vpcendpoints.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	used := map[string]bool{}
+	UsedFields(map[string]interface{}{
+		"forProvider":  map[string]interface{}{"region": "us-west-1"},
+		"initProvider": map[string]interface{}{"vpcId": "vpc-1"},
+	}, used)
+
+	got := NewFields(c.SpecSchema(), used)
+	want := Fields{
+		Total:            6,
+		Tested:           2,
+		Percent:          33.3,
+		Untested:         []string{"serviceName", "tags", "vpcIdRef.name", "vpcIdSelector.matchLabels"},
+		UntestedRequired: []string{"serviceName"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewFields() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,262 @@
+package coverage
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/FrangipaneTeam/bean/internal/results"
+)
+
+// Format is an output format of the report.
+type Format string
+
+const (
+	// FormatMarkdown is the markdown tables shown by the TUI.
+	FormatMarkdown Format = "markdown"
+	// FormatJSON is the JSON form of the report.
+	FormatJSON Format = "json"
+	// FormatYAML is the YAML form of the report.
+	FormatYAML Format = "yaml"
+	// FormatCSV is a line per kind with a header.
+	FormatCSV Format = "csv"
+	// FormatHTML is a standalone HTML page.
+	FormatHTML Format = "html"
+	// FormatJUnit is a JUnit XML report, a test suite per group, a skipped test case per untested kind
+	// and a failed one per kind whose last run failed.
+	FormatJUnit Format = "junit"
+)
+
+// Formats are the output formats of the report.
+var Formats = []Format{FormatMarkdown, FormatJSON, FormatYAML, FormatCSV, FormatHTML, FormatJUnit}
+
+// csvSeparator joins the versions and the examples of a kind in a CSV cell.
+const csvSeparator = ";"
+
+// ParseFormat returns the format named s.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(s) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %s, expected one of %v", s, Formats)
+}
+
+// Ext returns the extension of the files of the format.
+func (f Format) Ext() string {
+	switch f {
+	case FormatMarkdown:
+		return ".md"
+	case FormatJUnit:
+		return ".xml"
+	}
+	return "." + string(f)
+}
+
+// Write writes the report in the format.
+func Write(w io.Writer, format Format, r Report) error {
+	switch format {
+	case FormatMarkdown:
+		return markdownTemplate.Execute(w, r)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+		return enc.Close()
+	case FormatCSV:
+		return writeCSV(w, r)
+	case FormatHTML:
+		return htmlTemplate.Execute(w, r)
+	case FormatJUnit:
+		return writeJUnit(w, r)
+	}
+	return fmt.Errorf("unknown format %s, expected one of %v", format, Formats)
+}
+
+var markdownTemplate = template.Must(template.New("markdown").Parse(`
+# List of tested resources
+
+{{ .Tested }}/{{ .Total }} kinds have an example, {{ .Passed }} passed and {{ .Failed }} failed on their last run.
+{{ range .Groups }}
+## {{ .Name }}
+| Kind | Status | Versions | Examples | Last run | Fields |
+| ---- | ------ | -------- | -------- | -------- | ------ |{{ range .Kinds }}
+| {{ .Kind }} | {{ .StatusMark }}{{ if .DeprecatedOnly }} :warning: deprecated only{{ end }} | ` +
+	`{{ range $i, $v := .VersionCoverage }}{{ if $i }}, {{ end }}{{ $v.Name }}{{ if $v.Deprecated }} (deprecated){{ end }} ` +
+	`{{ if $v.Tested }}✓{{ else }}✗{{ end }}{{ end }} | ` +
+	`{{ range $i, $e := .Examples }}{{ if $i }}, {{ end }}[{{ $e }}]({{ $e }}){{ end }} | ` +
+	`{{ .LastRunSummary }} | ` +
+	`{{ .Fields.Percent }}% ({{ .Fields.Tested }}/{{ .Fields.Total }}) |{{ end }}
+{{ range .Kinds }}{{ if .Fields.Untested }}
+* {{ .Kind }} untested fields : {{ range $i, $f := .Fields.Untested }}{{ if $i }}, {{ end }}` + "`{{ $f }}`" + `{{ end }}{{ if .Fields.UntestedRequired }}
+  * required : {{ range $i, $f := .Fields.UntestedRequired }}{{ if $i }}, {{ end }}` + "`{{ $f }}`" + `{{ end }}{{ end }}{{ end }}{{ end }}
+{{ end }}
+`))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>List of tested resources</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+.tested { color: green; }
+.untested { color: red; }
+.notrun { color: gray; }
+</style>
+</head>
+<body>
+<h1>List of tested resources</h1>
+<p>{{ .Tested }} of {{ .Total }} kinds tested ({{ printf "%.1f" .Percent }}%), {{ .Passed }} passed and {{ .Failed }} failed on their last run</p>
+{{ range .Groups }}<h2>{{ .Name }}</h2>
+<table>
+<tr><th>Kind</th><th>Versions</th><th>Status</th><th>Examples</th><th>Last run</th><th>Fields</th><th>Untested fields</th></tr>
+{{ range .Kinds }}<tr>
+<td>{{ .Kind }}</td>
+<td>{{ range $i, $v := .VersionCoverage }}{{ if $i }}<br>{{ end }}{{ $v.Name }}{{ if $v.Deprecated }} (deprecated){{ end }} ` +
+	`{{ if $v.Tested }}<span class="tested">&#10004;</span>{{ else }}<span class="untested">&#10008;</span>{{ end }}{{ end }}</td>
+<td><span class="{{ .StatusClass }}">{{ .Status }}</span>` +
+	`{{ if .DeprecatedOnly }} <span class="untested">deprecated only</span>{{ end }}</td>
+<td>{{ range $i, $e := .Examples }}{{ if $i }}<br>{{ end }}<a href="{{ $e }}">{{ $e }}</a>{{ end }}</td>
+<td>{{ .LastRunSummary }}</td>
+<td>{{ .Fields.Percent }}% ({{ .Fields.Tested }}/{{ .Fields.Total }})</td>
+<td>{{ range $i, $f := .Fields.Untested }}{{ if $i }}<br>{{ end }}<code>{{ $f }}</code>{{ end }}</td>
+</tr>
+{{ end }}</table>
+{{ end }}</body>
+</html>
+`))
+
+func writeCSV(w io.Writer, r Report) error {
+	cw := csv.NewWriter(w)
+	header := []string{"group", "kind", "versions", "tested", "examples", "tested_versions", "deprecated_only",
+		"status", "last_run_time", "last_run_context", "last_run_provider_version", "last_run_error",
+		"fields_percent", "untested_fields", "untested_required_fields"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, k := range r.Kinds {
+		lastRun, lastRunTime := results.Outcome{}, ""
+		if k.LastRun != nil {
+			lastRun, lastRunTime = *k.LastRun, k.LastRun.Time.Format(time.RFC3339)
+		}
+		err := cw.Write([]string{
+			k.Group,
+			k.Kind,
+			strings.Join(k.Versions, csvSeparator),
+			strconv.FormatBool(k.Tested),
+			strings.Join(k.Examples, csvSeparator),
+			strings.Join(k.TestedVersions(), csvSeparator),
+			strconv.FormatBool(k.DeprecatedOnly),
+			string(k.Status),
+			lastRunTime,
+			lastRun.Context,
+			lastRun.ProviderVersion,
+			lastRun.Error,
+			strconv.FormatFloat(k.Fields.Percent, 'f', -1, 64),
+			strings.Join(k.Fields.Untested, csvSeparator),
+			strings.Join(k.Fields.UntestedRequired, csvSeparator),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+func writeJUnit(w io.Writer, r Report) error {
+	suites := junitTestSuites{Name: "coverage", Tests: r.Total}
+	for _, g := range r.Groups() {
+		suite := junitTestSuite{Name: g.Name, Tests: len(g.Kinds)}
+		for _, k := range g.Kinds {
+			tc := junitTestCase{
+				Name:      k.Kind,
+				ClassName: g.Name,
+				SystemOut: strings.Join(k.Examples, "\n"),
+			}
+			if len(k.Fields.Untested) > 0 {
+				tc.SystemOut = strings.TrimSpace(fmt.Sprintf("%s\nuntested fields: %s",
+					tc.SystemOut, strings.Join(k.Fields.Untested, ", ")))
+			}
+			if k.DeprecatedOnly {
+				tc.SystemOut = strings.TrimSpace(tc.SystemOut + "\nonly deprecated versions are tested")
+			}
+			switch {
+			case !k.Tested:
+				tc.Skipped = &junitSkipped{Message: "no example applies the kind"}
+				suite.Skipped++
+			case k.Status == results.StatusFailed:
+				tc.Failure = &junitFailure{Message: k.LastRun.Summary(), Text: k.LastRun.Error}
+				suite.Failures++
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
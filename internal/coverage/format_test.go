@@ -0,0 +1,87 @@
+package coverage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FrangipaneTeam/bean/internal/results"
+)
+
+// testReport returns a report with a kind of each status, one of them only tested by a deprecated version.
+func testReport() Report {
+	at := time.Date(2024, 5, 14, 9, 30, 0, 0, time.UTC)
+	r := Report{Total: 4, Tested: 3, Kinds: []Kind{
+		{
+			Group: "ec2", Kind: "Subnet", Versions: []string{"v1beta1"}, Tested: true,
+			Examples:        []string{"examples/ec2/subnet.yaml"},
+			VersionCoverage: []Version{{Name: "v1beta1", Storage: true, Tested: true, Examples: []string{"examples/ec2/subnet.yaml"}}},
+			Fields:          Fields{Total: 3, Tested: 2, Percent: 66.7, Untested: []string{"tags"}, UntestedRequired: []string{}},
+		},
+		{
+			Group: "ec2", Kind: "VPC", Versions: []string{"v1beta1"}, Tested: true,
+			Examples:        []string{"examples/ec2/vpc.yaml"},
+			VersionCoverage: []Version{{Name: "v1beta1", Storage: true, Tested: true, Examples: []string{"examples/ec2/vpc.yaml"}}},
+			Fields:          Fields{Total: 2, Tested: 2, Percent: 100, Untested: []string{}, UntestedRequired: []string{}},
+		},
+		{
+			Group: "ec2", Kind: "VPCEndpoint", Versions: []string{"v1alpha1", "v1beta1"}, Tested: true,
+			Examples: []string{"examples/ec2/vpcendpoint.yaml"},
+			VersionCoverage: []Version{
+				{Name: "v1alpha1", Deprecated: true, Tested: true, Examples: []string{"examples/ec2/vpcendpoint.yaml"}},
+				{Name: "v1beta1", Storage: true, Examples: []string{}},
+			},
+			DeprecatedOnly: true,
+			Fields: Fields{
+				Total: 6, Tested: 2, Percent: 33.3,
+				Untested:         []string{"serviceName", "tags"},
+				UntestedRequired: []string{"serviceName"},
+			},
+		},
+		{
+			Group: "s3", Kind: "Bucket", Versions: []string{"v1beta1"}, Examples: []string{},
+			VersionCoverage: []Version{{Name: "v1beta1", Storage: true, Examples: []string{}}},
+			Fields:          Fields{Untested: []string{}, UntestedRequired: []string{}},
+		},
+	}}
+	r.SetRuns(map[string]results.Outcome{
+		"examples/ec2/vpc.yaml": {
+			Example: "examples/ec2/vpc.yaml", Step: results.StepReady, Passed: true,
+			Time: at, Context: "kind-crossplane", ProviderVersion: "v0.42.0",
+		},
+		"examples/ec2/subnet.yaml": {
+			Example: "examples/ec2/subnet.yaml", Step: results.StepReady, Error: "Subnet/subnet (Synced: invalid cidr) not ready",
+			Time: at.Add(time.Minute), Context: "kind-crossplane",
+		},
+	})
+	return r
+}
+
+func TestWrite(t *testing.T) {
+	for _, format := range Formats {
+		t.Run(string(format), func(t *testing.T) {
+			var got bytes.Buffer
+			if err := Write(&got, format, testReport()); err != nil {
+				t.Fatal(err)
+			}
+			want, err := os.ReadFile(filepath.Join("testdata", "report"+format.Ext()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != string(want) {
+				t.Errorf("Write(%s) =\n%s\nwant\n%s", format, got.String(), want)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat("JUnit"); err != nil || f != FormatJUnit {
+		t.Errorf("ParseFormat(JUnit) = %s, %v, want %s", f, err, FormatJUnit)
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Error("ParseFormat(pdf) did not fail")
+	}
+}
@@ -0,0 +1,73 @@
+package coverage
+
+import (
+	"fmt"
+
+	"github.com/FrangipaneTeam/bean/internal/results"
+)
+
+var statusMarks = map[results.Status]string{
+	results.StatusNoExample: ":x: no example",
+	results.StatusNotRun:    ":grey_question: not run",
+	results.StatusPassed:    ":white_check_mark: passed",
+	results.StatusFailed:    ":red_circle: failed",
+}
+
+var statusClasses = map[results.Status]string{
+	results.StatusNoExample: "untested",
+	results.StatusNotRun:    "notrun",
+	results.StatusPassed:    "tested",
+	results.StatusFailed:    "untested",
+}
+
+// SetRuns sets the status of the kinds from the last runs of their examples, keyed by example file
+// relative to the provider path. The last run of a kind is the latest of its examples.
+func (r *Report) SetRuns(last map[string]results.Outcome) {
+	r.Passed, r.Failed = 0, 0
+	for i := range r.Kinds {
+		k := &r.Kinds[i]
+		k.Status, k.LastRun = results.StatusNoExample, nil
+		if !k.Tested {
+			continue
+		}
+
+		k.Status = results.StatusNotRun
+		for _, e := range k.Examples {
+			o, ok := last[e]
+			if !ok || (k.LastRun != nil && o.Time.Before(k.LastRun.Time)) {
+				continue
+			}
+			k.LastRun = &o
+			k.Status = o.Status()
+		}
+
+		switch k.Status {
+		case results.StatusPassed:
+			r.Passed++
+		case results.StatusFailed:
+			r.Failed++
+		}
+	}
+}
+
+// StatusMark returns the status of the kind for the markdown report.
+func (k Kind) StatusMark() string {
+	return statusMarks[k.Status]
+}
+
+// StatusClass returns the CSS class of the status of the kind in the HTML report.
+func (k Kind) StatusClass() string {
+	return statusClasses[k.Status]
+}
+
+// LastRunSummary returns the time, cluster context and provider version of the last run, empty if it never ran.
+func (k Kind) LastRunSummary() string {
+	if k.LastRun == nil {
+		return ""
+	}
+	s := fmt.Sprintf("%s on %s", k.LastRun.Time.Format("2006-01-02 15:04"), k.LastRun.Context)
+	if k.LastRun.ProviderVersion != "" {
+		s += ", provider " + k.LastRun.ProviderVersion
+	}
+	return s
+}
@@ -3,6 +3,7 @@ package crd
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -20,18 +21,57 @@ type CRD struct {
 			Plural     string   `yaml:"plural"`
 			Singular   string   `yaml:"singular"`
 		} `yaml:"names"`
-		Scope string `yaml:"scope"`
+		Scope    string    `yaml:"scope"`
+		Versions []Version `yaml:"versions"`
 	} `yaml:"spec"`
 }
 
-// GetCRDs returns a list of CRDs from the specified path.
+// Version is a version of a CRD.
+type Version struct {
+	Name       string `yaml:"name"`
+	Served     bool   `yaml:"served"`
+	Storage    bool   `yaml:"storage"`
+	Deprecated bool   `yaml:"deprecated"`
+	Schema     struct {
+		OpenAPIV3Schema *Schema `yaml:"openAPIV3Schema"`
+	} `yaml:"schema"`
+}
+
+// VersionNames returns the names of the versions of the CRD.
+func (c CRD) VersionNames() []string {
+	names := make([]string, 0, len(c.Spec.Versions))
+	for _, v := range c.Spec.Versions {
+		names = append(names, v.Name)
+	}
+	return names
+}
+
+// GetCRDs returns a list of CRDs found in the specified paths and their subdirectories.
 func GetCRDs(path ...string) ([]CRD, error) {
+	listFilesCrds, err := Files(path...)
+	if err != nil {
+		return nil, err
+	}
+
+	crds := make([]CRD, 0)
+	for _, file := range listFilesCrds {
+		w, err := ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		crds = append(crds, w)
+	}
+
+	return crds, nil
+}
+
+// Files returns the CRD files found in the specified paths and their subdirectories.
+func Files(path ...string) ([]string, error) {
 	if len(path) == 0 {
 		path = append(path, "package/crds/")
 	}
 
 	listFilesCrds := make([]string, 0)
-
 	for _, p := range path {
 		x, err := listFiles(p, ".yaml")
 		if err != nil {
@@ -40,47 +80,76 @@ func GetCRDs(path ...string) ([]CRD, error) {
 
 		listFilesCrds = append(listFilesCrds, x...)
 	}
+	return listFilesCrds, nil
+}
 
-	crds := make([]CRD, 0)
-	for _, file := range listFilesCrds {
-		w := &CRD{}
-
-		y, err := os.ReadFile(file)
-		if err != nil {
-			return nil, err
-		}
-
-		// Parse the file
-		err = yaml.Unmarshal(y, w)
-		if err != nil {
-			return nil, err
-		}
+// ReadFile parses the CRD of a file.
+func ReadFile(file string) (CRD, error) {
+	w := CRD{}
 
-		crds = append(crds, *w)
+	y, err := os.ReadFile(file)
+	if err != nil {
+		return w, err
 	}
 
-	return crds, nil
+	// Parse the file
+	err = yaml.Unmarshal(y, &w)
+	return w, err
 }
 
+// listFiles returns the files with the extension found in dir and its subdirectories.
 func listFiles(dir string, ext string) ([]string, error) {
 	// Check if the directory exists
 	if _, err := os.Stat(dir); err != nil {
 		return nil, fmt.Errorf("directory %s does not exist", dir)
 	}
 
-	// Get a list of files in the directory
-	files, err := os.ReadDir(dir)
+	// Walk the directory tree and keep the files with the specified extension
+	var filteredFiles []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && filepath.Ext(d.Name()) == ext {
+			filteredFiles = append(filteredFiles, path)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Filter the list of files to only include files with the specified extension
-	var filteredFiles []string
-	for _, file := range files {
-		if filepath.Ext(file.Name()) == ext {
-			filteredFiles = append(filteredFiles, fmt.Sprintf("%s/%s", dir, file.Name()))
+	return filteredFiles, nil
+}
+
+// StorageVersion returns the storage version of the CRD, the last one if none is marked as storage.
+func (c CRD) StorageVersion() *Version {
+	if len(c.Spec.Versions) == 0 {
+		return nil
+	}
+	for i, v := range c.Spec.Versions {
+		if v.Storage {
+			return &c.Spec.Versions[i]
 		}
 	}
+	return &c.Spec.Versions[len(c.Spec.Versions)-1]
+}
+
+// SpecSchema returns the schema of the spec of the storage version, nil if the CRD has no schema.
+func (c CRD) SpecSchema() *Schema {
+	v := c.StorageVersion()
+	if v == nil {
+		return nil
+	}
+	return v.Schema.OpenAPIV3Schema.Property("spec")
+}
 
-	return filteredFiles, nil
+// Version returns the version of the CRD named name, nil if it does not exist.
+func (c CRD) Version(name string) *Version {
+	for i, v := range c.Spec.Versions {
+		if v.Name == name {
+			return &c.Spec.Versions[i]
+		}
+	}
+	return nil
 }
@@ -0,0 +1,264 @@
+package crd
+
+import (
+	"sort"
+	"strings"
+)
+
+// Diff is the changes between two sets of CRDs. The kinds are named group/kind.
+type Diff struct {
+	AddedKinds   []string
+	RemovedKinds []string
+	// Kinds are the changes of the kinds of both sets, sorted by name.
+	Kinds []KindDiff
+}
+
+// KindDiff is the changes of a kind.
+type KindDiff struct {
+	Group           string
+	Kind            string
+	AddedVersions   []string
+	RemovedVersions []string
+	// OldStorage and NewStorage are the storage versions, set only if the storage version changed.
+	OldStorage string
+	NewStorage string
+	// Versions are the changes of the spec fields of the versions of both sets.
+	Versions []VersionDiff
+}
+
+// VersionDiff is the changes of the spec fields of a version, paths relative to the spec.
+type VersionDiff struct {
+	Version       string
+	AddedFields   []string
+	RemovedFields []string
+	Renamed       []Rename
+}
+
+// Rename is a field removed in favour of an added one of the same type.
+type Rename struct {
+	From string
+	To   string
+}
+
+// Empty returns true if the sets have the same kinds, versions and fields.
+func (d Diff) Empty() bool {
+	return len(d.AddedKinds) == 0 && len(d.RemovedKinds) == 0 && len(d.Kinds) == 0
+}
+
+// Kind returns the changes of the kind group/kind, nil if it did not change.
+func (d Diff) Kind(group, kind string) *KindDiff {
+	for i, k := range d.Kinds {
+		if k.Group == group && k.Kind == kind {
+			return &d.Kinds[i]
+		}
+	}
+	return nil
+}
+
+// IsRemoved returns true if the kind group/kind was removed.
+func (d Diff) IsRemoved(group, kind string) bool {
+	name := group + "/" + kind
+	for _, k := range d.RemovedKinds {
+		if k == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Version returns the changes of the fields of the version, nil if they did not change.
+func (k KindDiff) Version(name string) *VersionDiff {
+	for i, v := range k.Versions {
+		if v.Version == name {
+			return &k.Versions[i]
+		}
+	}
+	return nil
+}
+
+// IsRemovedVersion returns true if the version was removed.
+func (k KindDiff) IsRemovedVersion(name string) bool {
+	for _, v := range k.RemovedVersions {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare returns the changes from the old CRDs to the new ones.
+func Compare(old, new []CRD) Diff {
+	before, after := byKind(old), byKind(new)
+	d := Diff{AddedKinds: []string{}, RemovedKinds: []string{}, Kinds: []KindDiff{}}
+
+	for _, name := range sortedKeys(after) {
+		if _, ok := before[name]; !ok {
+			d.AddedKinds = append(d.AddedKinds, name)
+		}
+	}
+	for _, name := range sortedKeys(before) {
+		n, ok := after[name]
+		if !ok {
+			d.RemovedKinds = append(d.RemovedKinds, name)
+			continue
+		}
+		if k := compareKind(before[name], n); k != nil {
+			d.Kinds = append(d.Kinds, *k)
+		}
+	}
+	return d
+}
+
+// compareKind returns the changes of a kind, nil if there are none.
+func compareKind(old, new CRD) *KindDiff {
+	k := KindDiff{Group: new.Spec.Group, Kind: new.Spec.Names.Kind}
+
+	for _, v := range new.Spec.Versions {
+		if old.Version(v.Name) == nil {
+			k.AddedVersions = append(k.AddedVersions, v.Name)
+		}
+	}
+	for _, v := range old.Spec.Versions {
+		n := new.Version(v.Name)
+		if n == nil {
+			k.RemovedVersions = append(k.RemovedVersions, v.Name)
+			continue
+		}
+		if vd := compareFields(v, *n); vd != nil {
+			k.Versions = append(k.Versions, *vd)
+		}
+	}
+
+	if o, n := old.StorageVersion(), new.StorageVersion(); o != nil && n != nil && o.Name != n.Name {
+		k.OldStorage, k.NewStorage = o.Name, n.Name
+	}
+
+	if len(k.AddedVersions) == 0 && len(k.RemovedVersions) == 0 && len(k.Versions) == 0 && k.OldStorage == "" {
+		return nil
+	}
+	return &k
+}
+
+// compareFields returns the changes of the spec fields of a version, nil if there are none.
+func compareFields(old, new Version) *VersionDiff {
+	before, after := specFields(old), specFields(new)
+	v := VersionDiff{Version: new.Name}
+
+	removed := []Field{}
+	for _, f := range before {
+		if _, ok := after[f.Path]; !ok {
+			removed = append(removed, f)
+		}
+	}
+	added := []Field{}
+	for _, f := range after {
+		if _, ok := before[f.Path]; !ok {
+			added = append(added, f)
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i].Path < removed[j].Path })
+	sort.Slice(added, func(i, j int) bool { return added[i].Path < added[j].Path })
+
+	v.Renamed, removed, added = renames(removed, added)
+	for _, f := range removed {
+		v.RemovedFields = append(v.RemovedFields, f.Path)
+	}
+	for _, f := range added {
+		v.AddedFields = append(v.AddedFields, f.Path)
+	}
+
+	if len(v.AddedFields) == 0 && len(v.RemovedFields) == 0 && len(v.Renamed) == 0 {
+		return nil
+	}
+	return &v
+}
+
+// renames pairs the removed and added fields of the same type whose paths only differ by their case
+// or underscores, or that are the only removed and added fields of their parent and type.
+// It returns the renames and the fields left.
+func renames(removed, added []Field) ([]Rename, []Field, []Field) {
+	key := func(f Field) string { return parent(f.Path) + " " + f.Type }
+	count := func(fields []Field) map[string]int {
+		c := map[string]int{}
+		for _, f := range fields {
+			c[key(f)]++
+		}
+		return c
+	}
+	removedCount, addedCount := count(removed), count(added)
+
+	pairs := []Rename{}
+	paired := map[string]bool{}
+	leftRemoved := []Field{}
+	for _, r := range removed {
+		match := -1
+		for i, a := range added {
+			if paired[a.Path] || a.Type != r.Type {
+				continue
+			}
+			if sameName(r.Path, a.Path) || (key(a) == key(r) && removedCount[key(r)] == 1 && addedCount[key(a)] == 1) {
+				match = i
+				break
+			}
+		}
+		if match < 0 {
+			leftRemoved = append(leftRemoved, r)
+			continue
+		}
+		paired[added[match].Path] = true
+		pairs = append(pairs, Rename{From: r.Path, To: added[match].Path})
+	}
+
+	leftAdded := []Field{}
+	for _, a := range added {
+		if !paired[a.Path] {
+			leftAdded = append(leftAdded, a)
+		}
+	}
+	return pairs, leftRemoved, leftAdded
+}
+
+func sameName(a, b string) bool {
+	normalize := func(s string) string { return strings.ReplaceAll(strings.ToLower(s), "_", "") }
+	return normalize(a) == normalize(b)
+}
+
+// parent returns the path of the parent of the field, empty for a top level field.
+func parent(path string) string {
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+// specFields returns the leaf fields of the spec of the version, keyed by path.
+func specFields(v Version) map[string]Field {
+	fields := map[string]Field{}
+	if v.Schema.OpenAPIV3Schema == nil {
+		return fields
+	}
+	for _, f := range v.Schema.OpenAPIV3Schema.Property("spec").Fields("") {
+		fields[f.Path] = f
+	}
+	return fields
+}
+
+func byKind(crds []CRD) map[string]CRD {
+	kinds := make(map[string]CRD, len(crds))
+	for _, c := range crds {
+		if c.Spec.Group == "" || c.Spec.Names.Kind == "" {
+			continue
+		}
+		kinds[c.Spec.Group+"/"+c.Spec.Names.Kind] = c
+	}
+	return kinds
+}
+
+func sortedKeys(m map[string]CRD) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
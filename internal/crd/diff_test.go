@@ -0,0 +1,118 @@
+package crd
+
+import (
+	"reflect"
+	"testing"
+)
+
+// version is a version of a test CRD with its forProvider fields and their type.
+type version struct {
+	name   string
+	fields map[string]string
+}
+
+// newCRD returns the CRD of the kind of the group ec2, its last version is the storage version.
+func newCRD(kind string, versions ...version) CRD {
+	var c CRD
+	c.Spec.Group = "ec2"
+	c.Spec.Names.Kind = kind
+	for i, v := range versions {
+		forProvider := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		for name, typ := range v.fields {
+			forProvider.Properties[name] = &Schema{Type: typ}
+		}
+		cv := Version{Name: v.name, Storage: i == len(versions)-1}
+		cv.Schema.OpenAPIV3Schema = &Schema{Type: "object", Properties: map[string]*Schema{
+			"spec": {Type: "object", Properties: map[string]*Schema{"forProvider": forProvider}},
+		}}
+		c.Spec.Versions = append(c.Spec.Versions, cv)
+	}
+	return c
+}
+
+func TestCompare(t *testing.T) {
+	v1 := version{name: "v1", fields: map[string]string{"region": "string", "cidr_block": "string"}}
+	tests := []struct {
+		name string
+		old  []CRD
+		new  []CRD
+		want Diff
+	}{
+		{
+			name: "same",
+			old:  []CRD{newCRD("VPC", v1)},
+			new:  []CRD{newCRD("VPC", v1)},
+			want: Diff{AddedKinds: []string{}, RemovedKinds: []string{}, Kinds: []KindDiff{}},
+		},
+		{
+			name: "kinds",
+			old:  []CRD{newCRD("VPC", v1), newCRD("Subnet", v1)},
+			new:  []CRD{newCRD("VPC", v1), newCRD("Route", v1)},
+			want: Diff{AddedKinds: []string{"ec2/Route"}, RemovedKinds: []string{"ec2/Subnet"}, Kinds: []KindDiff{}},
+		},
+		{
+			name: "versions and storage",
+			old:  []CRD{newCRD("VPC", version{name: "v1alpha1"}, v1)},
+			new:  []CRD{newCRD("VPC", v1, version{name: "v2", fields: v1.fields})},
+			want: Diff{AddedKinds: []string{}, RemovedKinds: []string{}, Kinds: []KindDiff{{
+				Group: "ec2", Kind: "VPC", AddedVersions: []string{"v2"}, RemovedVersions: []string{"v1alpha1"},
+				OldStorage: "v1", NewStorage: "v2",
+			}}},
+		},
+		{
+			name: "fields",
+			old:  []CRD{newCRD("VPC", v1)},
+			new: []CRD{newCRD("VPC", version{name: "v1", fields: map[string]string{
+				"cidrBlock": "string", "tags": "object", "enableDns": "boolean",
+			}})},
+			want: Diff{AddedKinds: []string{}, RemovedKinds: []string{}, Kinds: []KindDiff{{
+				Group: "ec2", Kind: "VPC", Versions: []VersionDiff{{
+					Version:       "v1",
+					AddedFields:   []string{"forProvider.enableDns", "forProvider.tags"},
+					RemovedFields: []string{"forProvider.region"},
+					Renamed:       []Rename{{From: "forProvider.cidr_block", To: "forProvider.cidrBlock"}},
+				}},
+			}}},
+		},
+		{
+			name: "only field removed and added of its parent and type",
+			old:  []CRD{newCRD("VPC", v1)},
+			new: []CRD{newCRD("VPC", version{name: "v1", fields: map[string]string{
+				"location": "string", "cidr_block": "string",
+			}})},
+			want: Diff{AddedKinds: []string{}, RemovedKinds: []string{}, Kinds: []KindDiff{{
+				Group: "ec2", Kind: "VPC", Versions: []VersionDiff{{
+					Version: "v1",
+					Renamed: []Rename{{From: "forProvider.region", To: "forProvider.location"}},
+				}},
+			}}},
+		},
+		{
+			name: "field of another type",
+			old:  []CRD{newCRD("VPC", v1)},
+			new: []CRD{newCRD("VPC", version{name: "v1", fields: map[string]string{
+				"region": "string", "cidrBlock": "array",
+			}})},
+			want: Diff{AddedKinds: []string{}, RemovedKinds: []string{}, Kinds: []KindDiff{{
+				Group: "ec2", Kind: "VPC", Versions: []VersionDiff{{
+					Version:       "v1",
+					AddedFields:   []string{"forProvider.cidrBlock"},
+					RemovedFields: []string{"forProvider.cidr_block"},
+					Renamed:       []Rename{},
+				}},
+			}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(tt.old, tt.new)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compare() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+			wantEmpty := len(tt.want.AddedKinds)+len(tt.want.RemovedKinds)+len(tt.want.Kinds) == 0
+			if got.Empty() != wantEmpty {
+				t.Errorf("Compare().Empty() = %v, want %v", got.Empty(), wantEmpty)
+			}
+		})
+	}
+}
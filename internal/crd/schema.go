@@ -0,0 +1,205 @@
+package crd
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is an openAPIV3Schema of a CRD version, or one of its properties.
+type Schema struct {
+	Type        string             `yaml:"type"`
+	Format      string             `yaml:"format"`
+	Description string             `yaml:"description"`
+	Properties  map[string]*Schema `yaml:"properties"`
+	Items       *Schema            `yaml:"items"`
+	Required    []string           `yaml:"required"`
+	Enum        []interface{}      `yaml:"enum"`
+	// AdditionalProperties is set if the object is a map.
+	AdditionalProperties *AdditionalProperties `yaml:"additionalProperties"`
+	PreserveUnknown      bool                  `yaml:"x-kubernetes-preserve-unknown-fields"`
+	IntOrString          bool                  `yaml:"x-kubernetes-int-or-string"`
+	Validations          []Validation          `yaml:"x-kubernetes-validations"`
+}
+
+// Validation is a CEL validation rule of a schema.
+type Validation struct {
+	Rule    string `yaml:"rule"`
+	Message string `yaml:"message"`
+}
+
+// requiredParameterMessage ends the message of the rules of the required parameters of the upjet resources.
+const requiredParameterMessage = " is a required parameter"
+
+// reHas matches the has(self.path) macros of a rule.
+var reHas = regexp.MustCompile(`has\(self\.([A-Za-z0-9_]+(?:\.[A-Za-z0-9_]+)*)\)`)
+
+// RequiredParameter is a field required by a validation rule.
+type RequiredParameter struct {
+	// Path is the dotted path of the field, relative to the schema of the rule.
+	Path string
+	// Alternatives are the paths of the fields satisfying the rule, Path first.
+	Alternatives []string
+}
+
+// AdditionalProperties are the values allowed by an object besides its properties.
+type AdditionalProperties struct {
+	Allowed bool
+	// Schema is the schema of the values, nil if any value is allowed.
+	Schema *Schema
+}
+
+// UnmarshalYAML accepts a boolean or the schema of the values.
+func (a *AdditionalProperties) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!bool" {
+		a.Allowed = n.Value == "true"
+		return nil
+	}
+	a.Allowed = true
+	return n.Decode(&a.Schema)
+}
+
+// IsMap returns true if the object accepts any key.
+func (s *Schema) IsMap() bool {
+	return s.AdditionalProperties != nil && s.AdditionalProperties.Allowed || s.PreserveUnknown
+}
+
+// RequiredParameters returns the fields required by the validation rules of the schema. Upjet declares the
+// required parameters of a managed resource as rules of its spec, with the message
+// "spec.forProvider.region is a required parameter" and a rule like
+// `has(self.forProvider.region) || (has(self.initProvider) && has(self.initProvider.region))`.
+func (s *Schema) RequiredParameters() []RequiredParameter {
+	if s == nil {
+		return nil
+	}
+	params := []RequiredParameter{}
+	for _, v := range s.Validations {
+		if !strings.HasSuffix(v.Message, requiredParameterMessage) {
+			continue
+		}
+
+		paths := []string{}
+		for _, m := range reHas.FindAllStringSubmatch(v.Rule, -1) {
+			paths = append(paths, m[1])
+		}
+		// has(self.initProvider) only guards has(self.initProvider.region)
+		p := RequiredParameter{}
+		for _, path := range paths {
+			if !isParentOfAny(path, paths) {
+				p.Alternatives = append(p.Alternatives, path)
+			}
+		}
+		if len(p.Alternatives) > 0 {
+			p.Path = p.Alternatives[0]
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+func isParentOfAny(path string, paths []string) bool {
+	for _, p := range paths {
+		if strings.HasPrefix(p, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Field is a leaf field of a schema.
+type Field struct {
+	// Path is the dotted path of the field, [] stands for the items of a list.
+	Path string
+	Type string
+	// Required is true if the field and all its parents are required.
+	Required bool
+}
+
+// Property returns the schema of the dotted path, nil if it does not exist.
+func (s *Schema) Property(path ...string) *Schema {
+	for _, p := range path {
+		if s == nil {
+			return nil
+		}
+		s = s.Properties[p]
+	}
+	return s
+}
+
+// RequiredProperties returns the names of the properties required by the object, by its required list or by the
+// validation rules of the schema of parent, the property of the object in parent, sorted.
+func (s *Schema) RequiredProperties(parent *Schema, property string) []string {
+	names := append([]string{}, s.Required...)
+	for _, p := range parent.RequiredParameters() {
+		if name, ok := strings.CutPrefix(p.Path, property+"."); ok && !strings.Contains(name, ".") {
+			names = append(names, name)
+		}
+	}
+	for _, p := range s.RequiredParameters() {
+		if !strings.Contains(p.Path, ".") {
+			names = append(names, p.Path)
+		}
+	}
+	sort.Strings(names)
+	return compact(names)
+}
+
+func compact(sorted []string) []string {
+	kept := sorted[:0]
+	for i, name := range sorted {
+		if i == 0 || name != sorted[i-1] {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
+
+// IsRequired returns true if the property name is required by the object.
+func (s *Schema) IsRequired(name string) bool {
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Fields returns the leaf fields of the schema, sorted by path. Maps and objects without properties are leaves.
+// The fields required by the validation rules are required.
+func (s *Schema) Fields(prefix string) []Field {
+	fields := []Field{}
+	s.fields(prefix, true, map[string]bool{}, &fields)
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+	return fields
+}
+
+// fields walks the schema, ruled are the paths of the fields required by the rules of the parent schemas.
+func (s *Schema) fields(path string, required bool, ruled map[string]bool, fields *[]Field) {
+	if s == nil {
+		return
+	}
+	for _, p := range s.RequiredParameters() {
+		ruled[join(path, p.Path)] = true
+	}
+
+	switch {
+	case len(s.Properties) > 0:
+		for name, p := range s.Properties {
+			childPath := join(path, name)
+			p.fields(childPath, required && (s.IsRequired(name) || ruled[childPath]), ruled, fields)
+		}
+	case s.Type == "array" && s.Items != nil && len(s.Items.Properties) > 0:
+		s.Items.fields(path+"[]", required, ruled, fields)
+	default:
+		*fields = append(*fields, Field{Path: path, Type: s.Type, Required: required})
+	}
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
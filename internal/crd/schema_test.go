@@ -0,0 +1,73 @@
+package crd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// readVPCEndpoint reads the CRD of the fixture, the required parameters of its spec are rules like upjet's.
+func readVPCEndpoint(t *testing.T) CRD {
+	t.Helper()
+	c, err := ReadFile(filepath.Join("testdata", "ec2.aws.upbound.io_vpcendpoints.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestRequiredParameters(t *testing.T) {
+	got := readVPCEndpoint(t).SpecSchema().RequiredParameters()
+	want := []RequiredParameter{
+		{Path: "forProvider.region", Alternatives: []string{"forProvider.region"}},
+		{Path: "forProvider.serviceName", Alternatives: []string{"forProvider.serviceName", "initProvider.serviceName"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RequiredParameters() = %v, want %v", got, want)
+	}
+}
+
+func TestRequiredProperties(t *testing.T) {
+	spec := readVPCEndpoint(t).SpecSchema()
+	tests := []struct {
+		property string
+		want     []string
+	}{
+		{"forProvider", []string{"region", "serviceName"}},
+		{"initProvider", []string{}},
+		{"providerConfigRef", []string{"name"}},
+	}
+	for _, tt := range tests {
+		if got := spec.Property(tt.property).RequiredProperties(spec, tt.property); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("RequiredProperties(%s) = %v, want %v", tt.property, got, tt.want)
+		}
+	}
+}
+
+func TestFieldsRequired(t *testing.T) {
+	required := map[string]bool{}
+	for _, f := range readVPCEndpoint(t).SpecSchema().Fields("") {
+		required[f.Path] = f.Required
+	}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"forProvider.region", true},
+		{"forProvider.serviceName", true},
+		{"forProvider.vpcId", false},
+		{"forProvider.vpcIdRef.name", false},
+		{"initProvider.serviceName", false},
+		{"providerConfigRef.name", false},
+	}
+	for _, tt := range tests {
+		got, ok := required[tt.path]
+		if !ok {
+			t.Errorf("no field %s", tt.path)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("field %s required = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -1,12 +1,23 @@
 package examples
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/FrangipaneTeam/bean/config"
+	"github.com/FrangipaneTeam/bean/internal/cache"
+	"github.com/FrangipaneTeam/bean/internal/crd"
 	"github.com/FrangipaneTeam/bean/internal/exlist"
+	"github.com/FrangipaneTeam/bean/internal/lint"
+	"github.com/FrangipaneTeam/bean/internal/render"
+	"github.com/FrangipaneTeam/bean/internal/results"
+	"github.com/FrangipaneTeam/bean/internal/secrets"
 	yml "github.com/FrangipaneTeam/bean/pkg/yaml"
 	"github.com/FrangipaneTeam/bean/tui/pages/errorpanel"
 	"github.com/charmbracelet/bubbles/list"
@@ -14,67 +25,203 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-// GenerateExamplesList generates the list of examples.
+// GenerateExamplesList generates the list of examples found in the examples directories and their subdirectories.
 func GenerateExamplesList(c config.Provider) tea.Msg {
-	examplesList, err := listDirExamples(c.Path)
+	roots, err := c.ExamplesRoots()
 	if err != nil {
 		return errorpanel.ErrorMsg{
 			Reason: "could not read examples directory",
 			Cause:  err,
 		}
 	}
+
 	var s exlist.LoadedExamples
 	s.Examples = make(map[string][]list.Item)
-	rootExamples := []list.Item{}
+	s.Examples[exlist.RootDir] = []list.Item{}
+
+	// a single examples directory is the root of the list, unless it has its own examples
+	s.Base = c.Path
+	if len(roots) == 1 && !hasYamlFiles(roots[0]) {
+		s.Base = roots[0]
+	}
+
+	vars, err := c.ProfileVars()
+	if err != nil {
+		return errorpanel.ErrorMsg{
+			Reason: "could not load the profile",
+			Cause:  err,
+		}
+	}
+	// the examples and the CRDs that did not change since the previous run are read from the cache
+	idx := cache.Open(c.Path)
+	opts := loadOptions{sidecars: c.Sidecars(), vars: vars, cache: idx, excluded: c.ExamplesExcluded}
+	if s.CRDs, s.CRDsErr = readCRDs(c, idx); s.CRDsErr == nil {
+		opts.linter = lint.New(s.CRDs)
+	}
+	opts.context = opts.cacheContext(idx.CRDsSum)
 
-	for _, dir := range examplesList {
-		if !dir.IsDir() {
+	examplesWithDependencies := exlist.ExamplesDetails{}
+	for i, root := range roots {
+		if isInside(roots[:i], root) {
 			continue
 		}
-		// Generate file dependencies
-		dirName := dir.Name()
 
-		items, errMsg := createExampleList(c.Path + "/examples/" + dirName)
+		count, errMsg := addDirectory(&s, examplesWithDependencies, root, opts)
 		if errMsg != nil {
 			return errMsg
 		}
 
-		for _, item := range items {
-			s.Examples[dirName] = append(s.Examples[dirName], item)
+		if root == s.Base || count == 0 {
+			continue
 		}
+		s.Examples[exlist.RootDir] = append(s.Examples[exlist.RootDir], newDirectory(s.Base, root, count))
+	}
 
-		e := &exlist.Example{
-			FileName: dirName,
-			FullPath: dirName,
-			Desc:     fmt.Sprintf("%d examples", len(s.Examples[dirName])),
+	s.Graph = examplesWithDependencies.FindDependencies()
+	examplesWithDependencies.CheckConventions(exlist.RelativeName(s.Base))
+	disabled := c.DisabledRules()
+	for _, example := range examplesWithDependencies {
+		example.Diagnostics = lint.Filter(example.Diagnostics, disabled)
+	}
+	// the results are optional to browse the examples, a broken store is reported by the listTested command
+	last, _ := results.Last(c.Path)
+	for _, example := range examplesWithDependencies {
+		if o, ok := last[results.Name(c.Path, example.FullPath)]; ok {
+			example.LastRun = o.Summary()
 		}
-		rootExamples = append(rootExamples, e)
 	}
 
-	s.Examples["-"] = rootExamples
+	// the cache is optional, the examples are parsed again next time if it can't be written
+	idx.Prune()
+	_ = idx.Save()
+	return s
+}
 
-	examplesWithDependencies := exlist.ExamplesDetails{}
-	for d, e := range s.Examples {
-		if d == "-" {
+// readCRDs returns the CRDs of the provider.
+// The CRDs are optional to browse the examples, their errors are reported by the lint command.
+func readCRDs(c config.Provider, idx *cache.Index) ([]crd.CRD, error) {
+	roots, err := c.CRDsRoots()
+	if err != nil {
+		return nil, err
+	}
+	return idx.CRDs(roots...)
+}
+
+// loadOptions are the options of the examples loading.
+type loadOptions struct {
+	sidecars config.Sidecars
+	// vars are the variables of the active profile rendered in the files before they are parsed.
+	vars render.Vars
+	// linter validates the examples against their CRDs, nil if the CRDs can't be read.
+	linter *lint.Linter
+	// cache holds the examples parsed by the previous runs, valid for the same context.
+	cache   *cache.Index
+	context string
+	// excluded returns true for the directories and files excluded by the examples key.
+	excluded func(path string) bool
+}
+
+// cacheContext returns the sum of what the parsing depends on besides the files, crdsSum is the sum of the CRDs.
+func (opts loadOptions) cacheContext(crdsSum string) string {
+	if opts.linter == nil {
+		crdsSum = ""
+	}
+	return cache.Context(cache.VarsContext(opts.vars), opts.sidecars.Extra, opts.sidecars.Secret, crdsSum)
+}
+
+// addDirectory adds the list of dir, its subdirectories first, then its examples.
+// It returns the number of examples found in dir and its subdirectories, empty directories are not listed.
+func addDirectory(s *exlist.LoadedExamples, details exlist.ExamplesDetails, dir string, opts loadOptions) (int, *errorpanel.ErrorMsg) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, &errorpanel.ErrorMsg{
+			Reason: "could not read examples directory",
+			Cause:  err,
+		}
+	}
+
+	items := []list.Item{}
+	count := 0
+	for _, entry := range entries {
+		subDir := filepath.Join(dir, entry.Name())
+		if !entry.IsDir() || opts.excluded(subDir) {
 			continue
 		}
-		for _, ex := range e {
-			example, ok := ex.(*exlist.Example)
-			if ok {
-				examplesWithDependencies[example.ExampleID] = example
-			}
+
+		subCount, errMsg := addDirectory(s, details, subDir, opts)
+		if errMsg != nil {
+			return 0, errMsg
 		}
+		if subCount == 0 {
+			continue
+		}
+
+		count += subCount
+		items = append(items, newDirectory(dir, subDir, subCount))
 	}
-	examplesWithDependencies.FindDependencies()
-	return s
+
+	examples, errMsg := createExampleList(dir, opts)
+	if errMsg != nil {
+		return 0, errMsg
+	}
+	for _, e := range examples {
+		details[e.FullPath] = e
+		items = append(items, e)
+	}
+	count += len(examples)
+
+	if count > 0 {
+		key := s.Key(dir)
+		s.Examples[key] = append(s.Examples[key], items...)
+	}
+	return count, nil
+}
+
+// newDirectory returns the list item of dir, named relative to parent.
+func newDirectory(parent, dir string, count int) *exlist.Example {
+	name, err := filepath.Rel(parent, dir)
+	if err != nil {
+		name = filepath.Base(dir)
+	}
+
+	return &exlist.Example{
+		FileName:  name,
+		FullPath:  dir,
+		Desc:      directoryDesc(count),
+		Directory: true,
+	}
+}
+
+// directoryDesc returns the description of a directory of count examples.
+func directoryDesc(count int) string {
+	return fmt.Sprintf("%d examples", count)
 }
 
-func listDirExamples(path string) ([]os.DirEntry, error) {
-	examplesList, err := os.ReadDir(fmt.Sprintf("%s/examples", path))
-	return examplesList, err
+// hasYamlFiles returns true if dir contains yaml files, without looking in its subdirectories.
+func hasYamlFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && yml.IsYamlFile(entry.Name()) {
+			return true
+		}
+	}
+	return false
 }
 
-func createExampleList(dir string) ([]*exlist.Example, *errorpanel.ErrorMsg) {
+// isInside returns true if dir is one of the roots or one of their subdirectories.
+func isInside(roots []string, dir string) bool {
+	for _, root := range roots {
+		if rel, err := filepath.Rel(root, dir); err == nil && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+	return false
+}
+
+func createExampleList(dir string, opts loadOptions) ([]*exlist.Example, *errorpanel.ErrorMsg) {
 	kindList, err := os.ReadDir(dir)
 	if err != nil {
 		return nil, &errorpanel.ErrorMsg{
@@ -87,83 +234,182 @@ func createExampleList(dir string) ([]*exlist.Example, *errorpanel.ErrorMsg) {
 
 	// for the sub list of examples dir
 	for _, sf := range kindList {
-		if !sf.Type().IsRegular() {
+		if !sf.Type().IsRegular() || opts.excluded(dir+"/"+sf.Name()) {
 			continue
 		}
 
-		// get only yaml files
-		if isYaml := yml.IsYamlFile(sf.Name()); !isYaml {
-			continue
+		k, errMsg := readExampleFile(dir+"/"+sf.Name(), opts)
+		if errMsg != nil {
+			return nil, errMsg
+		}
+		if k != nil {
+			exampleList = append(exampleList, k)
 		}
+	}
+	return exampleList, nil
+}
+
+// readExampleFile reads the example of a yaml file with its sidecar files.
+// It returns nil if the file is not a yaml file, does not exist or has no resource.
+func readExampleFile(path string, opts loadOptions) (*exlist.Example, *errorpanel.ErrorMsg) {
+	name := filepath.Base(path)
+
+	// get only yaml files
+	if isYaml := yml.IsYamlFile(name); !isYaml {
+		return nil, nil
+	}
 
-		// open and parse yaml file
-		yfile, errReadFile := os.ReadFile(
-			fmt.Sprintf("%s/%s", dir, sf.Name()),
-		)
-		if errReadFile != nil {
+	extraFile, secretFile := path+opts.sidecars.Extra, path+opts.sidecars.Secret
+	if k, ok := opts.cache.Example(path, extraFile, secretFile, opts.context); ok {
+		return k, nil
+	}
+	stamps := cache.Stamps(path, extraFile, secretFile)
+
+	// open and parse yaml file
+	yfile, errReadFile := os.ReadFile(path)
+	if errors.Is(errReadFile, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if errReadFile != nil {
+		return nil, &errorpanel.ErrorMsg{
+			Reason: "could not read examples directory",
+			Cause:  errReadFile,
+		}
+	}
+	// render the profile variables so that the references are found in the applied content
+	yfile, renderDiags := renderFile(path, yfile, opts.vars)
+	k, errMsg := parseExample(yfile)
+	switch {
+	case len(renderDiags) > 0 && (errMsg != nil || k == nil):
+		// a file that can't be rendered is listed with its issue, the other examples are loaded
+		k = &exlist.Example{Desc: "not rendered"}
+	case errMsg != nil:
+		return nil, errMsg
+	case k == nil:
+		// continue if unmarshal empty yaml
+		opts.cache.PutExample(path, opts.context, stamps, nil)
+		return nil, nil
+	default:
+		k.Desc = describeResources(k.Resources)
+		k.Diagnostics = opts.linter.Lint(path, yfile)
+		k.ExampleID = strings.ToLower(fmt.Sprintf("%s.%s", k.Kind, k.APIVersion))
+	}
+
+	k.FullPath = path
+	k.FileName = name
+	k.Diagnostics = append(renderDiags, k.Diagnostics...)
+
+	// check for refs and selectors
+	k.References = k.FindReferences()
+	k.DependenciesFiles = map[string]bool{}
+
+	// check for extra files
+	extraFileCount, errCheckExtra := checkForExtraFile(extraFile, opts.vars, &k.Diagnostics)
+	if errCheckExtra != nil {
+		return nil, errCheckExtra
+	}
+	if extraFileCount > 0 {
+		k.ExtraFileExist = true
+		k.ExtraFile = extraFile
+		k.Desc = fmt.Sprintf("%s + %d extra", k.Desc, extraFileCount)
+	}
+
+	// check for secret file
+	extraSecretCount, encryption, errCheckSecret := checkForSecretFile(secretFile, opts.vars, &k.Diagnostics)
+	if errCheckSecret != nil {
+		return nil, errCheckSecret
+	}
+	if extraSecretCount > 0 {
+		k.SecretFileExist = true
+		k.SecretFile = secretFile
+		k.SecretEncryption = string(encryption)
+		k.Desc = fmt.Sprintf("%s + %d secret", k.Desc, extraSecretCount)
+		if encryption != secrets.FormatPlain {
+			k.Desc = fmt.Sprintf("%s (%s)", k.Desc, encryption)
+		}
+	}
+	opts.cache.PutExample(path, opts.context, stamps, k)
+	return k, nil
+}
+
+// parseExample parses all the documents of an example file, nil if there are none.
+func parseExample(yfile []byte) (*exlist.Example, *errorpanel.ErrorMsg) {
+	dec := yaml.NewDecoder(bytes.NewReader(yfile))
+	resources := []exlist.Resource{}
+	for {
+		doc := &yaml.Node{}
+		err := dec.Decode(doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
 			return nil, &errorpanel.ErrorMsg{
-				Reason: "could not read examples directory",
-				Cause:  errReadFile,
+				Reason: "split yaml error",
+				Cause:  err,
 			}
 		}
-		var k *exlist.Example
-		err = yaml.Unmarshal(yfile, &k)
-		if err != nil {
+
+		var r exlist.Resource
+		if err = doc.Decode(&r); err != nil {
 			return nil, &errorpanel.ErrorMsg{
 				Reason: "unmarshal error",
 				Cause:  err,
 			}
 		}
 
-		// continue if unmarshal empty yaml
-		if k == nil {
+		// skip empty documents
+		if r.Kind == "" && r.APIVersion == "" {
 			continue
 		}
+		r.Line = doc.Content[0].Line
+		resources = append(resources, r)
+	}
 
-		k.FullPath = dir + "/" + sf.Name()
-		k.FileName = sf.Name()
-		k.Desc = k.Kind + " → " + k.APIVersion
-		k.ExampleID = strings.ToLower(fmt.Sprintf("%s.%s", k.Kind, k.APIVersion))
-
-		// check for selector
-		k.Selectors, k.Refs = k.FindSelectorsAndRefs()
-		k.DependenciesFiles = map[string]bool{}
+	if len(resources) == 0 {
+		return nil, nil
+	}
 
-		// check for extra files
-		extraFileCount, errCheckExtra := checkForExtraFile(dir, sf.Name())
-		if errCheckExtra != nil {
-			return nil, errCheckExtra
-		}
-		if extraFileCount > 0 {
-			k.ExtraFileExist = true
-			k.Desc = fmt.Sprintf("%s + %d extra", k.Desc, extraFileCount)
-		}
+	return &exlist.Example{
+		Resource:  resources[0],
+		Resources: resources,
+	}, nil
+}
 
-		// check for secret file
-		extraSecretCount, errCheckSecret := checkForSecretFile(dir, sf.Name())
-		if errCheckSecret != nil {
-			return nil, errCheckSecret
-		}
-		if extraSecretCount > 0 {
-			k.SecretFileExist = true
-			k.Desc = fmt.Sprintf("%s + %d secret", k.Desc, extraSecretCount)
-		}
+// describeResources returns the kinds and versions of the resources of an example.
+func describeResources(resources []exlist.Resource) string {
+	desc := make([]string, 0, len(resources))
+	for _, r := range resources {
+		desc = append(desc, r.Kind+" → "+r.APIVersion)
+	}
+	return strings.Join(desc, ", ")
+}
 
-		exampleList = append(exampleList, k)
+// renderFile renders the variables of the profile in a file. A file that can't be rendered is read with its
+// ${VAR} variables only and the error is returned as a diagnostic, so the loading of the other examples goes on.
+func renderFile(path string, data []byte, vars render.Vars) ([]byte, []lint.Diagnostic) {
+	rendered, err := vars.Render(path, data)
+	if err == nil {
+		return rendered, nil
 	}
-	return exampleList, nil
+	return vars.Substitute(data), []lint.Diagnostic{{
+		File:    path,
+		Line:    1,
+		Rule:    lint.RuleRender,
+		Message: fmt.Sprintf("could not render: %s", err),
+	}}
 }
 
-func checkForExtraFile(dir string, file string) (int, *errorpanel.ErrorMsg) {
+// checkForExtraFile returns the number of documents of the extra file, adding its render issue to diags.
+func checkForExtraFile(path string, vars render.Vars, diags *[]lint.Diagnostic) (int, *errorpanel.ErrorMsg) {
 	var (
 		extraK8S  *exlist.Example
 		extraKind int
 	)
 
-	extraYFile, err := os.ReadFile(
-		fmt.Sprintf("%s/%s.extra", dir, file),
-	)
+	extraYFile, err := os.ReadFile(path)
 	if err == nil {
+		extraYFile, renderDiags := renderFile(path, extraYFile, vars)
+		*diags = append(*diags, renderDiags...)
 		extraY, errSplitYaml := yml.SplitYAML(extraYFile)
 		if errSplitYaml != nil {
 			return extraKind, &errorpanel.ErrorMsg{
@@ -185,34 +431,44 @@ func checkForExtraFile(dir string, file string) (int, *errorpanel.ErrorMsg) {
 	return extraKind, nil
 }
 
-func checkForSecretFile(dir string, file string) (int, *errorpanel.ErrorMsg) {
+// checkForSecretFile returns the number of documents of the secret file and its encryption, adding its render
+// issue to diags. An age encrypted file counts as one document, it is only decrypted when applied.
+func checkForSecretFile(path string, vars render.Vars, diags *[]lint.Diagnostic) (int, secrets.Format, *errorpanel.ErrorMsg) {
 	var (
 		extraK8S  *exlist.Example
 		extraKind int
 	)
 
-	extraSFile, err := os.ReadFile(
-		fmt.Sprintf("%s/%s.secret", dir, file),
-	)
-	if err == nil {
-		extraY, errSplitYaml := yml.SplitYAML(extraSFile)
-		if errSplitYaml != nil {
-			return extraKind, &errorpanel.ErrorMsg{
-				Reason: "split yaml error",
-				Cause:  errSplitYaml,
-			}
+	extraSFile, err := os.ReadFile(path)
+	if err != nil {
+		return extraKind, secrets.FormatPlain, nil
+	}
+
+	encryption := secrets.Detect(extraSFile)
+	if encryption == secrets.FormatAge {
+		return 1, encryption, nil
+	}
+
+	extraSFile, renderDiags := renderFile(path, extraSFile, vars)
+	*diags = append(*diags, renderDiags...)
+
+	extraY, errSplitYaml := yml.SplitYAML(extraSFile)
+	if errSplitYaml != nil {
+		return extraKind, encryption, &errorpanel.ErrorMsg{
+			Reason: "split yaml error",
+			Cause:  errSplitYaml,
 		}
+	}
 
-		for _, f := range extraY {
-			err = yaml.Unmarshal(f, &extraK8S)
-			if err != nil {
-				return extraKind, &errorpanel.ErrorMsg{
-					Reason: "unmarshal error",
-					Cause:  err,
-				}
+	for _, f := range extraY {
+		err = yaml.Unmarshal(f, &extraK8S)
+		if err != nil {
+			return extraKind, encryption, &errorpanel.ErrorMsg{
+				Reason: "unmarshal error",
+				Cause:  err,
 			}
-			extraKind++
 		}
+		extraKind++
 	}
-	return extraKind, nil
+	return extraKind, encryption, nil
 }
@@ -0,0 +1,200 @@
+package examples
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/FrangipaneTeam/bean/config"
+	"github.com/FrangipaneTeam/bean/internal/cache"
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+	"github.com/spf13/viper"
+)
+
+const (
+	profiles = `profiles:
+  dev:
+    REGION: us-west-1
+  prod:
+    REGION: eu-west-3
+`
+	vpc = `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: VPC
+metadata:
+  name: vpc
+  labels:
+    testing.upbound.io/example-name: vpc
+spec:
+  forProvider:
+    region: ${REGION}
+`
+	subnet = `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: Subnet
+metadata:
+  name: subnet
+spec:
+  forProvider:
+    region: ${REGION}
+    vpcIdSelector:
+      matchLabels:
+        testing.upbound.io/example-name: vpc
+`
+)
+
+// newProvider returns a provider path with the config of the profiles, in temporary cache and state dirs.
+func newProvider(t *testing.T) string {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, config.FileName), profiles)
+	return dir
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testConfig returns the config of the provider path with the profile active.
+func testConfig(t *testing.T, dir, profile string) config.Provider {
+	t.Helper()
+	f, err := config.ReadFile(filepath.Join(dir, config.FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := config.Provider{Path: dir, Files: []*config.File{f}, Profile: profile, Viper: viper.New()}
+	if err = config.Merge(c.Viper, c.Files); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// load loads the examples of the provider path with the profile active.
+func load(t *testing.T, dir, profile string) exlist.LoadedExamples {
+	t.Helper()
+	loaded, ok := GenerateExamplesList(testConfig(t, dir, profile)).(exlist.LoadedExamples)
+	if !ok {
+		t.Fatal("GenerateExamplesList() did not load the examples")
+	}
+	return loaded
+}
+
+// regions returns the region of each example, keyed by resource name.
+func regions(loaded exlist.LoadedExamples) map[string]string {
+	got := map[string]string{}
+	for _, e := range loaded.List() {
+		forProvider, _ := e.Spec["forProvider"].(map[string]interface{})
+		region, _ := forProvider["region"].(string)
+		got[e.Metadata.Name] = region
+	}
+	return got
+}
+
+func TestGenerateExamplesListCache(t *testing.T) {
+	dir := newProvider(t)
+	vpcFile := filepath.Join(dir, "examples", "ec2", "vpc.yaml")
+	subnetFile := filepath.Join(dir, "examples", "ec2", "subnet.yaml")
+	writeFile(t, vpcFile, vpc)
+	writeFile(t, subnetFile, subnet)
+
+	steps := []struct {
+		name    string
+		change  func()
+		profile string
+		want    map[string]string
+	}{
+		{"first load", func() {}, "dev", map[string]string{"vpc": "us-west-1", "subnet": "us-west-1"}},
+		{"cached", func() {}, "dev", map[string]string{"vpc": "us-west-1", "subnet": "us-west-1"}},
+		{"profile switched", func() {}, "prod", map[string]string{"vpc": "eu-west-3", "subnet": "eu-west-3"}},
+		{
+			name: "profile variable changed",
+			change: func() {
+				writeFile(t, filepath.Join(dir, config.FileName), strings.ReplaceAll(profiles, "eu-west-3", "ap-south-1"))
+			},
+			profile: "prod",
+			want:    map[string]string{"vpc": "ap-south-1", "subnet": "ap-south-1"},
+		},
+		{
+			name:    "file edited",
+			change:  func() { writeFile(t, vpcFile, strings.ReplaceAll(vpc, "${REGION}", "us-east-1")) },
+			profile: "dev",
+			want:    map[string]string{"vpc": "us-east-1", "subnet": "us-west-1"},
+		},
+		{
+			name:    "file deleted",
+			change:  func() { os.Remove(subnetFile) },
+			profile: "dev",
+			want:    map[string]string{"vpc": "us-east-1"},
+		},
+	}
+	for _, st := range steps {
+		st.change()
+		loaded := load(t, dir, st.profile)
+		if got := regions(loaded); !reflect.DeepEqual(got, st.want) {
+			t.Errorf("%s: regions = %v, want %v", st.name, got, st.want)
+		}
+	}
+
+	// the deleted example is dropped from the cache
+	paths := []string{}
+	for path := range cache.Open(dir).ExampleFiles {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	if want := []string{vpcFile}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("cached files = %v, want %v", paths, want)
+	}
+}
+
+func TestGenerateExamplesListRecursive(t *testing.T) {
+	dir := newProvider(t)
+	writeFile(t, filepath.Join(dir, config.FileName), profiles+`examples:
+  - examples
+  - examples-generated
+  - "!examples/*/v1alpha1"
+  - "!examples-generated/ec2/draft.yaml"
+`)
+	writeFile(t, filepath.Join(dir, "examples", "ec2", "v1beta1", "vpc.yaml"), vpc)
+	writeFile(t, filepath.Join(dir, "examples", "ec2", "v1beta1", "subnet.yaml"), subnet)
+	writeFile(t, filepath.Join(dir, "examples", "ec2", "v1alpha1", "vpc.yaml"), vpc)
+	writeFile(t, filepath.Join(dir, "examples-generated", "ec2", "vpc.yaml"), vpc)
+	writeFile(t, filepath.Join(dir, "examples-generated", "ec2", "draft.yaml"), subnet)
+	if err := os.MkdirAll(filepath.Join(dir, "examples", "s3"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := load(t, dir, "dev")
+	rel := exlist.RelativeName(loaded.Base)
+	got := map[string][]string{}
+	for key, items := range loaded.Examples {
+		for _, item := range items {
+			e := item.(*exlist.Example)
+			name := e.FileName
+			if e.Directory {
+				name += ": " + e.Desc
+			}
+			got[rel(key)] = append(got[rel(key)], name)
+		}
+	}
+	// the excluded and the empty directories are not listed
+	want := map[string][]string{
+		exlist.RootDir:           {"examples: 2 examples", "examples-generated: 1 examples"},
+		"examples":               {"ec2: 2 examples"},
+		"examples/ec2":           {"v1beta1: 2 examples"},
+		"examples/ec2/v1beta1":   {"subnet.yaml", "vpc.yaml"},
+		"examples-generated":     {"ec2: 1 examples"},
+		"examples-generated/ec2": {"vpc.yaml"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GenerateExamplesList() =\n%v\nwant\n%v", got, want)
+	}
+}
@@ -0,0 +1,235 @@
+package examples
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/FrangipaneTeam/bean/config"
+	"github.com/FrangipaneTeam/bean/internal/cache"
+	"github.com/FrangipaneTeam/bean/internal/crd"
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+	"github.com/FrangipaneTeam/bean/internal/lint"
+	"github.com/FrangipaneTeam/bean/internal/results"
+	yml "github.com/FrangipaneTeam/bean/pkg/yaml"
+	"github.com/FrangipaneTeam/bean/tui/pages/errorpanel"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ChangedFilesMsg holds the example files changed since the examples were loaded.
+type ChangedFilesMsg struct {
+	Files []string
+}
+
+// ReadFilesMsg holds the examples of the changed files read again, keyed by path.
+// The example of a file that was deleted or has no resource left is nil.
+type ReadFilesMsg struct {
+	Examples map[string]*exlist.Example
+	// NewDirectory is true if a directory was created, the examples must then be loaded again.
+	NewDirectory bool
+}
+
+// ReadFiles reads again the examples of the changed files, the example of a sidecar file is read again with it.
+// The examples are checked against the CRDs they were loaded with.
+func ReadFiles(c config.Provider, crds []crd.CRD, files []string) tea.Cmd {
+	return func() tea.Msg {
+		vars, err := c.ProfileVars()
+		if err != nil {
+			return errorpanel.ErrorMsg{
+				Reason: "could not load the profile",
+				Cause:  err,
+			}
+		}
+		idx := cache.Open(c.Path)
+		opts := loadOptions{sidecars: c.Sidecars(), vars: vars, cache: idx, excluded: c.ExamplesExcluded}
+		if crds != nil {
+			opts.linter = lint.New(crds)
+		}
+		opts.context = opts.cacheContext(idx.CRDsSum)
+
+		read := make(map[string]*exlist.Example, len(files))
+		for _, f := range files {
+			if isDir(f) {
+				return ReadFilesMsg{NewDirectory: true}
+			}
+			path := exampleOf(filepath.Clean(f), opts.sidecars)
+			if _, done := read[path]; done || !yml.IsYamlFile(path) || opts.excluded(path) {
+				continue
+			}
+			e, errMsg := readExampleFile(path, opts)
+			if errMsg != nil {
+				return *errMsg
+			}
+			read[path] = e
+		}
+		// the cache is optional, the examples are parsed again next time if it can't be written
+		_ = idx.Save()
+		return ReadFilesMsg{Examples: read}
+	}
+}
+
+// exampleOf returns the example file of a sidecar file, the file itself otherwise.
+func exampleOf(file string, sidecars config.Sidecars) string {
+	for _, suffix := range []string{sidecars.Extra, sidecars.Secret} {
+		if suffix != "" && strings.HasSuffix(file, suffix) {
+			return strings.TrimSuffix(file, suffix)
+		}
+	}
+	return file
+}
+
+// Patch applies the examples read again to the loaded ones, on the goroutine showing them:
+// their items in the lists of their directories, the dependency edges from and to them and the
+// dependencies of the examples depending on them. The conventions are checked again on all the examples.
+// It returns false when the directories of the list change, the examples must then be loaded again.
+func Patch(c config.Provider, loaded exlist.LoadedExamples, read map[string]*exlist.Example) bool {
+	roots, err := c.ExamplesRoots()
+	if err != nil || loaded.Graph == nil {
+		return false
+	}
+
+	details := exlist.ExamplesDetails{}
+	for _, e := range loaded.List() {
+		details[e.FullPath] = e
+	}
+
+	removedFrom := map[string]int{}
+	for path, e := range read {
+		old := details[path]
+		dir := filepath.Dir(path)
+		items, listed := loaded.Examples[loaded.Key(dir)]
+		switch {
+		case old == nil && e == nil:
+			continue
+		case (old == nil || e == nil) && isRoot(roots, dir):
+			// the base of the list depends on the files of the examples directories
+			return false
+		case old == nil && !listed:
+			return false
+		case e == nil:
+			removedFrom[dir]++
+			if removedFrom[dir] >= len(items) {
+				return false
+			}
+		}
+	}
+
+	// the results are optional to browse the examples, a broken store is reported by the listTested command
+	last, _ := results.Last(c.Path)
+	changed := exlist.ExamplesDetails{}
+	removed := []string{}
+	counts := map[string]bool{}
+	for path, e := range read {
+		old := details[path]
+		if old == nil && e == nil {
+			continue
+		}
+
+		key := loaded.Key(filepath.Dir(path))
+		switch {
+		case e == nil:
+			loaded.Examples[key] = removeItem(loaded.Examples[key], old)
+			delete(details, path)
+			removed = append(removed, path)
+			counts[filepath.Dir(path)] = true
+			continue
+		case old == nil:
+			loaded.Examples[key] = insertItem(loaded.Examples[key], e)
+			counts[filepath.Dir(path)] = true
+		default:
+			loaded.Examples[key] = replaceItem(loaded.Examples[key], old, e)
+			e.Readiness, e.ReadinessFailed = old.Readiness, old.ReadinessFailed
+		}
+		if o, ok := last[results.Name(c.Path, path)]; ok {
+			e.LastRun = o.Summary()
+		}
+		details[path] = e
+		changed[path] = e
+	}
+
+	for dir := range counts {
+		updateCounts(loaded, roots, details, dir)
+	}
+
+	for _, path := range loaded.Graph.Update(changed, removed) {
+		loaded.Graph.SetDependencies(details[path])
+	}
+
+	details.CheckConventions(exlist.RelativeName(loaded.Base))
+	disabled := c.DisabledRules()
+	for _, example := range details {
+		example.Diagnostics = lint.Filter(example.Diagnostics, disabled)
+	}
+	return true
+}
+
+// updateCounts sets the number of examples of dir and its parents in their directory items.
+func updateCounts(loaded exlist.LoadedExamples, roots []string, details exlist.ExamplesDetails, dir string) {
+	for ; dir != loaded.Base; dir = filepath.Dir(dir) {
+		count := 0
+		for path := range details {
+			if strings.HasPrefix(path, dir+"/") {
+				count++
+			}
+		}
+
+		parent := exlist.RootDir
+		if !isRoot(roots, dir) {
+			parent = loaded.Key(filepath.Dir(dir))
+		}
+		for _, item := range loaded.Examples[parent] {
+			if d, ok := item.(*exlist.Example); ok && d.IsDir() && d.FullPath == dir {
+				d.Desc = directoryDesc(count)
+			}
+		}
+
+		if isRoot(roots, dir) {
+			return
+		}
+	}
+}
+
+// isRoot returns true if dir is one of the examples directories.
+func isRoot(roots []string, dir string) bool {
+	for _, root := range roots {
+		if filepath.Clean(root) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// insertItem inserts the example among the examples of a list, sorted by file name after the directories.
+func insertItem(items []list.Item, e *exlist.Example) []list.Item {
+	i := 0
+	for ; i < len(items); i++ {
+		if other, ok := items[i].(*exlist.Example); ok && !other.IsDir() && other.FileName > e.FileName {
+			break
+		}
+	}
+	items = append(items, nil)
+	copy(items[i+1:], items[i:])
+	items[i] = e
+	return items
+}
+
+// replaceItem replaces the old example of a list with the new one.
+func replaceItem(items []list.Item, old, e *exlist.Example) []list.Item {
+	for i, item := range items {
+		if item == list.Item(old) {
+			items[i] = e
+		}
+	}
+	return items
+}
+
+// removeItem removes the example from a list.
+func removeItem(items []list.Item, e *exlist.Example) []list.Item {
+	kept := make([]list.Item, 0, len(items))
+	for _, item := range items {
+		if item != list.Item(e) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,142 @@
+package examples
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+)
+
+const (
+	bucket = `apiVersion: s3.aws.upbound.io/v1beta1
+kind: Bucket
+metadata:
+  name: bucket
+spec:
+  forProvider:
+    region: ${REGION}
+`
+	securityGroup = `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: SecurityGroup
+metadata:
+  name: securitygroup
+spec:
+  forProvider:
+    region: ${REGION}
+    vpcIdRef:
+      name: vpc
+`
+)
+
+// summary returns the items of the lists of the loaded examples with their description, dependencies and
+// diagnostics, and the edges of the graph, to compare a patched loading with a full one.
+func summary(loaded exlist.LoadedExamples) map[string][]string {
+	rel := exlist.RelativeName(loaded.Base)
+	s := map[string][]string{}
+	for key, items := range loaded.Examples {
+		for _, item := range items {
+			e := item.(*exlist.Example)
+			layers := [][]string{}
+			for _, layer := range e.DependenciesLayers {
+				names := []string{}
+				for _, f := range layer {
+					names = append(names, rel(f))
+				}
+				layers = append(layers, names)
+			}
+			rules := []string{}
+			for _, d := range e.Diagnostics {
+				rules = append(rules, string(d.Rule))
+			}
+			s[rel(key)] = append(s[rel(key)], fmt.Sprintf("%s: %s, layers %v, diagnostics %v",
+				rel(e.FullPath), e.Desc, layers, rules))
+		}
+	}
+	for _, edge := range loaded.Graph.Edges(loaded.Graph.Reachable()) {
+		s["edges"] = append(s["edges"], fmt.Sprintf("%s -> %s", rel(edge.From), rel(edge.To)))
+	}
+	return s
+}
+
+func TestPatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(t *testing.T, examples string) []string
+		// reload is true if the patch can't be applied, the examples must be loaded again
+		reload bool
+	}{
+		{
+			name: "changed",
+			change: func(t *testing.T, examples string) []string {
+				file := filepath.Join(examples, "ec2", "vpc.yaml")
+				writeFile(t, file, strings.ReplaceAll(vpc, "example-name: vpc", "example-name: main"))
+				return []string{file}
+			},
+		},
+		{
+			name: "added",
+			change: func(t *testing.T, examples string) []string {
+				file := filepath.Join(examples, "ec2", "securitygroup.yaml")
+				writeFile(t, file, securityGroup)
+				return []string{file}
+			},
+		},
+		{
+			name: "deleted",
+			change: func(t *testing.T, examples string) []string {
+				file := filepath.Join(examples, "ec2", "vpc.yaml")
+				os.Remove(file)
+				return []string{file}
+			},
+		},
+		{
+			name: "sidecar added",
+			change: func(t *testing.T, examples string) []string {
+				file := filepath.Join(examples, "s3", "bucket.yaml.extra")
+				writeFile(t, file, "apiVersion: v1\nkind: Secret\nmetadata:\n  name: bucket\n")
+				return []string{file}
+			},
+		},
+		{
+			name: "directory emptied",
+			change: func(t *testing.T, examples string) []string {
+				file := filepath.Join(examples, "s3", "bucket.yaml")
+				os.Remove(file)
+				return []string{file}
+			},
+			reload: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newProvider(t)
+			examples := filepath.Join(dir, "examples")
+			writeFile(t, filepath.Join(examples, "ec2", "vpc.yaml"), vpc)
+			writeFile(t, filepath.Join(examples, "ec2", "subnet.yaml"), subnet)
+			writeFile(t, filepath.Join(examples, "s3", "bucket.yaml"), bucket)
+			c := testConfig(t, dir, "dev")
+			patched := load(t, dir, "dev")
+
+			files := tt.change(t, examples)
+			msg, ok := ReadFiles(c, patched.CRDs, files)().(ReadFilesMsg)
+			if !ok || msg.NewDirectory {
+				t.Fatalf("ReadFiles() = %v, want the examples read again", msg)
+			}
+			if got := Patch(c, patched, msg.Examples); got == tt.reload {
+				t.Fatalf("Patch() = %v, want %v", got, !tt.reload)
+			}
+			if tt.reload {
+				return
+			}
+
+			got, want := summary(patched), summary(load(t, dir, "dev"))
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Patch() =\n%v\nwant the full loading\n%v", got, want)
+			}
+		})
+	}
+}
@@ -3,16 +3,16 @@ package examples
 
 import (
 	"errors"
-	"fmt"
 	"log"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/FrangipaneTeam/bean/config"
 	yml "github.com/FrangipaneTeam/bean/pkg/yaml"
 	"github.com/FrangipaneTeam/bean/tui/pages/errorpanel"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dietsche/rfsnotify"
-	"gopkg.in/fsnotify.v1"
 )
 
 // NotifyActivity is a struct that holds the name of the file that was changed.
@@ -36,7 +36,7 @@ func LoadExamples(c config.Provider) tea.Cmd {
 // ListenForCRDActivity watches for crd file changes and sends a message to the channel.
 func ListenForCRDActivity(ch chan NotifyActivity, c config.Provider) tea.Cmd {
 	return func() tea.Msg {
-		watcher, err := newWatcher()
+		watcher, err := newRecursiveWatcher()
 		if err != nil {
 			return errorpanel.ErrorMsg{
 				Reason: "NewWatcher error",
@@ -48,7 +48,7 @@ func ListenForCRDActivity(ch chan NotifyActivity, c config.Provider) tea.Cmd {
 		done := make(chan bool)
 		go watchCRDFiles(watcher, done, ch)
 
-		err = addCRDFolder(watcher, c.Path)
+		err = addCRDFolder(watcher, c)
 		if err != nil {
 			return errorpanel.ErrorMsg{
 				Reason: "NewWatcher error",
@@ -60,11 +60,7 @@ func ListenForCRDActivity(ch chan NotifyActivity, c config.Provider) tea.Cmd {
 	}
 }
 
-func newWatcher() (*fsnotify.Watcher, error) {
-	return fsnotify.NewWatcher()
-}
-
-func watchCRDFiles(watcher *fsnotify.Watcher, done chan bool, ch chan NotifyActivity) tea.Msg {
+func watchCRDFiles(watcher *rfsnotify.RWatcher, done chan bool, ch chan NotifyActivity) tea.Msg {
 	defer close(done)
 
 	for {
@@ -93,8 +89,12 @@ func watchCRDFiles(watcher *fsnotify.Watcher, done chan bool, ch chan NotifyActi
 	}
 }
 
-func addCRDFolder(watcher *fsnotify.Watcher, path string) error {
-	return watcher.Add(fmt.Sprintf("%s/package/crds", path))
+func addCRDFolder(watcher *rfsnotify.RWatcher, c config.Provider) error {
+	roots, err := c.CRDsRoots()
+	if err != nil {
+		return err
+	}
+	return addFolders(watcher, roots)
 }
 
 // ListenForExamplesActivity watches for examples file changes and sends a message to the channel.
@@ -110,9 +110,9 @@ func ListenForExamplesActivity(ch chan NotifyActivity, c config.Provider) tea.Cm
 		defer watcher.Close()
 
 		done := make(chan bool)
-		go watchExamplesFiles(watcher, done, ch)
+		go watchExamplesFiles(watcher, done, ch, c.Sidecars())
 
-		err = addExamplesFolder(watcher, c.Path)
+		err = addExamplesFolder(watcher, c)
 		if err != nil {
 			return errorpanel.ErrorMsg{
 				Reason: "NewWatcher error",
@@ -124,7 +124,7 @@ func ListenForExamplesActivity(ch chan NotifyActivity, c config.Provider) tea.Cm
 	}
 }
 
-func watchExamplesFiles(watcher *rfsnotify.RWatcher, done chan bool, ch chan NotifyActivity) {
+func watchExamplesFiles(watcher *rfsnotify.RWatcher, done chan bool, ch chan NotifyActivity, sidecars config.Sidecars) {
 	defer close(done)
 
 	for {
@@ -133,7 +133,8 @@ func watchExamplesFiles(watcher *rfsnotify.RWatcher, done chan bool, ch chan Not
 			if !ok {
 				return
 			}
-			if isExamplesFile(event.Name) {
+			// a new directory may hold files created before it was watched
+			if isExamplesFile(event.Name, sidecars) || isDir(event.Name) {
 				f := NotifyActivity{
 					FileName: event.Name,
 				}
@@ -148,13 +149,33 @@ func watchExamplesFiles(watcher *rfsnotify.RWatcher, done chan bool, ch chan Not
 	}
 }
 
-func isExamplesFile(fileName string) bool {
+func isExamplesFile(fileName string, sidecars config.Sidecars) bool {
 	ext := filepath.Ext(fileName)
-	return ext == ".yaml" || ext == ".yml" || ext == ".secret" || ext == ".extra"
+	return ext == ".yaml" || ext == ".yml" ||
+		strings.HasSuffix(fileName, sidecars.Secret) || strings.HasSuffix(fileName, sidecars.Extra)
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
 }
 
-func addExamplesFolder(watcher *rfsnotify.RWatcher, path string) error {
-	return watcher.AddRecursive(fmt.Sprintf("%s/examples", path))
+func addExamplesFolder(watcher *rfsnotify.RWatcher, c config.Provider) error {
+	roots, err := c.ExamplesRoots()
+	if err != nil {
+		return err
+	}
+	return addFolders(watcher, roots)
+}
+
+// addFolders watches the folders and their subfolders.
+func addFolders(watcher *rfsnotify.RWatcher, folders []string) error {
+	for _, folder := range folders {
+		if err := watcher.AddRecursive(folder); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func newRecursiveWatcher() (*rfsnotify.RWatcher, error) {
@@ -0,0 +1,173 @@
+package exlist
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/FrangipaneTeam/bean/internal/kube"
+	"github.com/FrangipaneTeam/bean/internal/lint"
+)
+
+// AnnotationExampleID is the annotation of the example id, group/version/kind of the file.
+const AnnotationExampleID = "meta.upbound.io/example-id"
+
+// occurrence is a resource of an example.
+type occurrence struct {
+	example *Example
+	line    int
+}
+
+// conventionRules are the rules checked by CheckConventions.
+var conventionRules = map[lint.Rule]bool{
+	lint.RuleExampleID:      true,
+	lint.RuleDuplicateName:  true,
+	lint.RuleDuplicateLabel: true,
+	lint.RuleDanglingRef:    true,
+	lint.RuleProviderConfig: true,
+}
+
+// CheckConventions adds the diagnostics of the conventions of the upjet examples to the examples:
+// the example ids, the duplicated names and labels, the dangling refs and selectors and the missing
+// providerConfigRef of the managed resources. name returns the name of an example file in the messages.
+// The diagnostics of a previous check are replaced, so it can run again after some examples changed.
+func (examples ExamplesDetails) CheckConventions(name func(string) string) {
+	paths := make([]string, 0, len(examples))
+	for path, e := range examples {
+		paths = append(paths, path)
+		e.Diagnostics = dropRules(e.Diagnostics, conventionRules)
+	}
+	sort.Strings(paths)
+
+	names := map[string][]occurrence{}
+	labels := map[string][]occurrence{}
+	for _, path := range paths {
+		e := examples[path]
+		e.checkExampleID()
+		for _, r := range e.Resources {
+			if !kube.IsManagedSpec(r.Spec) {
+				continue
+			}
+			if r.Metadata.Name != "" {
+				key := r.Kind + "/" + r.Metadata.Name
+				names[key] = append(names[key], occurrence{example: e, line: r.Line})
+			}
+			if label := r.ExampleName(); label != "" {
+				key := r.Kind + "/" + label
+				labels[key] = append(labels[key], occurrence{example: e, line: r.Line})
+			}
+			if _, ok := r.Spec["providerConfigRef"]; !ok {
+				e.addDiagnostic(r.Line, "spec.providerConfigRef", lint.RuleProviderConfig,
+					fmt.Sprintf("%s %s has no providerConfigRef", r.Kind, r.Metadata.Name))
+			}
+		}
+		e.checkReferences(examples)
+	}
+
+	duplicates(names, lint.RuleDuplicateName, "metadata.name", name)
+	duplicates(labels, lint.RuleDuplicateLabel, "metadata.labels."+LabelExampleName, name)
+
+	for _, e := range examples {
+		sort.SliceStable(e.Diagnostics, func(i, j int) bool { return e.Diagnostics[i].Line < e.Diagnostics[j].Line })
+	}
+}
+
+// ExpectedExampleID returns the example id of a resource, like ec2/v1beta1/vpc.
+func (r Resource) ExpectedExampleID() string {
+	group, version, _ := strings.Cut(r.APIVersion, "/")
+	short, _, _ := strings.Cut(group, ".")
+	return fmt.Sprintf("%s/%s/%s", short, version, strings.ToLower(r.Kind))
+}
+
+// checkExampleID checks that the resources have the example id of the first resource of the file.
+func (e *Example) checkExampleID() {
+	if len(e.Resources) == 0 || !kube.IsManagedSpec(e.Resources[0].Spec) {
+		return
+	}
+
+	expected := e.Resources[0].ExpectedExampleID()
+	for i, r := range e.Resources {
+		id := r.Metadata.Annotations.MetaUpboundIoExampleID
+		path := "metadata.annotations." + AnnotationExampleID
+		switch {
+		case id == "" && i == 0:
+			e.addDiagnostic(r.Line, path, lint.RuleExampleID, fmt.Sprintf("missing example-id, expected %s", expected))
+		case id != "" && id != expected:
+			e.addDiagnostic(r.Line, path, lint.RuleExampleID,
+				fmt.Sprintf("example-id %s does not match the kind of the file, expected %s", id, expected))
+		}
+	}
+}
+
+// checkReferences checks that the refs and the selectors of the example match a resource of the examples.
+func (e *Example) checkReferences(examples ExamplesDetails) {
+	for _, ref := range e.References {
+		found := false
+		for _, other := range examples {
+			if ref.MatchExample(other) != NoMatch {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		target := fmt.Sprintf("named %s", ref.Name)
+		if ref.IsSelector() {
+			target = fmt.Sprintf("labelled %s", formatLabels(ref.MatchLabels))
+		}
+		e.addDiagnostic(e.Resources[ref.Document].Line, "spec."+ref.Path, lint.RuleDanglingRef,
+			fmt.Sprintf("spec.%s: no example defines a resource %s", ref.Path, target))
+	}
+}
+
+func (e *Example) addDiagnostic(line int, path string, rule lint.Rule, msg string) {
+	e.Diagnostics = append(e.Diagnostics, lint.Diagnostic{
+		File:    e.FullPath,
+		Line:    line,
+		Path:    path,
+		Rule:    rule,
+		Message: msg,
+	})
+}
+
+// duplicates adds a diagnostic to each resource sharing its key with others.
+func duplicates(byKey map[string][]occurrence, rule lint.Rule, path string, name func(string) string) {
+	for key, occurrences := range byKey {
+		if len(occurrences) < 2 {
+			continue
+		}
+		kind, value, _ := strings.Cut(key, "/")
+		for i, o := range occurrences {
+			others := make([]string, 0, len(occurrences)-1)
+			for j, other := range occurrences {
+				if i != j {
+					others = append(others, fmt.Sprintf("%s:%d", name(other.example.FullPath), other.line))
+				}
+			}
+			o.example.addDiagnostic(o.line, path, rule,
+				fmt.Sprintf("%s %s of %s is also used by %s", path, value, kind, strings.Join(others, ", ")))
+		}
+	}
+}
+
+// dropRules returns the diagnostics that are not of the rules.
+func dropRules(diags []lint.Diagnostic, rules map[lint.Rule]bool) []lint.Diagnostic {
+	kept := diags[:0:0]
+	for _, d := range diags {
+		if !rules[d.Rule] {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,116 @@
+package exlist
+
+import (
+	"reflect"
+	"testing"
+)
+
+const (
+	conventionVPC = `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: VPC
+metadata:
+  name: example
+  annotations:
+    meta.upbound.io/example-id: ec2/v1beta1/vpc
+  labels:
+    testing.upbound.io/example-name: example
+spec:
+  forProvider:
+    region: us-west-1
+  providerConfigRef:
+    name: default
+`
+	conventionSubnet = `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: Subnet
+metadata:
+  name: example
+  annotations:
+    meta.upbound.io/example-id: ec2/v1beta1/subnet
+spec:
+  forProvider:
+    region: us-west-1
+    vpcIdSelector:
+      matchLabels:
+        testing.upbound.io/example-name: example
+  providerConfigRef:
+    name: default
+`
+	providerConfig = `apiVersion: aws.upbound.io/v1beta1
+kind: ProviderConfig
+metadata:
+  name: default
+spec:
+  credentials:
+    source: Secret
+`
+)
+
+func TestCheckConventions(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources map[string]string
+		want      map[string][]string
+	}{
+		{
+			name:      "valid",
+			resources: map[string]string{"vpc": conventionVPC, "subnet": conventionSubnet},
+			want:      map[string][]string{"vpc": {}, "subnet": {}},
+		},
+		{
+			name:      "duplicated names and labels",
+			resources: map[string]string{"vpc": conventionVPC, "other": conventionVPC},
+			want: map[string][]string{
+				"vpc": {
+					"duplicate-name metadata.name",
+					"duplicate-label metadata.labels.testing.upbound.io/example-name",
+				},
+				"other": {
+					"duplicate-name metadata.name",
+					"duplicate-label metadata.labels.testing.upbound.io/example-name",
+				},
+			},
+		},
+		{
+			name:      "dangling selector",
+			resources: map[string]string{"subnet": conventionSubnet},
+			want:      map[string][]string{"subnet": {"dangling-ref spec.forProvider.vpcIdSelector"}},
+		},
+		{
+			name: "missing example-id and providerConfigRef",
+			resources: map[string]string{"vpc": `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: VPC
+metadata:
+  name: example
+spec:
+  forProvider:
+    region: us-west-1
+`},
+			want: map[string][]string{"vpc": {
+				"example-id metadata.annotations.meta.upbound.io/example-id",
+				"provider-config spec.providerConfigRef",
+			}},
+		},
+		{
+			name:      "provider config",
+			resources: map[string]string{"providerconfig": providerConfig, "other": providerConfig},
+			want:      map[string][]string{"providerconfig": {}, "other": {}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			all := examples(t, tt.resources)
+			all.CheckConventions(func(path string) string { return path })
+
+			got := map[string][]string{}
+			for path, e := range all {
+				got[path] = []string{}
+				for _, d := range e.Diagnostics {
+					got[path] = append(got[path], string(d.Rule)+" "+d.Path)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckConventions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
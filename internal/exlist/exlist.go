@@ -2,17 +2,21 @@ package exlist
 
 import (
 	"fmt"
-	"os"
-	"regexp"
 	"sort"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
-	"golang.org/x/exp/maps"
+
+	"github.com/FrangipaneTeam/bean/internal/crd"
+	"github.com/FrangipaneTeam/bean/internal/lint"
 )
 
-var (
-	reRefs     = regexp.MustCompile(`^\w+Refs$`)
-	reSelector = regexp.MustCompile(`^\w+Selector$`)
+const (
+	// LabelExampleName is the label used by the examples to select each other.
+	LabelExampleName = "testing.upbound.io/example-name"
+
+	// RootDir is the key of the root list of the examples.
+	RootDir = "-"
 )
 
 // Example is a struct that holds the details of an example.
@@ -23,30 +27,65 @@ type Example struct {
 	Desc            string
 	ExtraFileExist  bool
 	SecretFileExist bool
-	Selectors       map[string]bool
-	Refs            map[string]bool
+	// ExtraFile and SecretFile are the paths of the sidecar files applied with the example.
+	ExtraFile  string
+	SecretFile string
+	// SecretEncryption is the encryption of the secret file, age or sops, empty if it is in plain text.
+	SecretEncryption string
+	References       []Reference
+	// Directory is true if the item is a directory of examples, its FullPath is the key of its list.
+	Directory bool
 
 	DependenciesFiles map[string]bool
+	// DependenciesLayers are the dependencies files in apply order, each layer only depends on the previous ones.
+	DependenciesLayers [][]string
+	// DependenciesErr is set when the dependencies can't be ordered.
+	DependenciesErr error
+	// DependenciesWarning is set when the dependencies are ordered by ignoring the ambiguous refs of a cycle.
+	DependenciesWarning string
+
+	// Diagnostics are the issues found by the validation of the example against its CRDs.
+	Diagnostics []lint.Diagnostic
+
+	// Readiness is the progress of the last apply, ReadinessFailed is true if it failed.
+	Readiness       string
+	ReadinessFailed bool
+	// LastRun is the summary of the last run of the example recorded in the results store, empty if it never ran.
+	LastRun string
+
+	// Resource is the first resource of the file.
+	Resource `yaml:",inline"`
+	// Resources are all the resources of the file, the first one included.
+	Resources []Resource `yaml:"-"`
+}
 
+// Resource is a kubernetes resource defined in an example file.
+type Resource struct {
 	APIVersion string `yaml:"apiVersion"`
 	Kind       string `yaml:"kind"`
 	Metadata   struct {
 		Annotations struct {
 			MetaUpboundIoExampleID string `yaml:"meta.upbound.io/example-id"`
 		} `yaml:"annotations"`
-		Labels struct {
-			TestingUpboundIoExampleName string `yaml:"testing.upbound.io/example-name"`
-		} `yaml:"labels"`
-		Name string `yaml:"name"`
+		Labels map[string]string `yaml:"labels"`
+		Name   string            `yaml:"name"`
 	} `yaml:"metadata"`
-	Spec struct {
-		ForProvider map[string]interface{} `yaml:"forProvider"`
-	} `yaml:"spec"`
+	Spec map[string]interface{} `yaml:"spec"`
+	// Line is the line of the document of the resource in its file.
+	Line int `yaml:"-"`
 }
 
 // LoadedExamples is a struct that holds the loaded examples.
+// Examples are keyed by directory path, the root list by RootDir.
 type LoadedExamples struct {
 	Examples map[string][]list.Item
+	Graph    *Graph
+	// Base is the directory of the root list, the example names are relative to it.
+	Base string
+	// CRDs are the CRDs the examples were checked against, nil if they can't be read.
+	CRDs []crd.CRD
+	// CRDsErr is the error reading the CRDs, the examples are loaded without validation.
+	CRDsErr error
 }
 
 // ListTestedDone is a struct that holds the done message.
@@ -62,7 +101,25 @@ func (e Example) Title() string { return e.FileName }
 func (e Example) FileWithPath() string { return e.FullPath }
 
 // Description returns the description of the example.
-func (e Example) Description() string { return e.Desc }
+func (e Example) Description() string {
+	desc := e.Desc
+	switch {
+	case e.DependenciesErr != nil:
+		desc += " ⚠ dependency cycle"
+	case e.DependenciesWarning != "":
+		desc += " ⚠ ambiguous dependency cycle"
+	}
+	if len(e.Diagnostics) > 0 {
+		desc = fmt.Sprintf("%s ⚠ %d lint issue(s)", desc, len(e.Diagnostics))
+	}
+	switch {
+	case e.Readiness != "":
+		return desc + " • " + e.Readiness
+	case e.LastRun != "":
+		return desc + " • " + e.LastRun
+	}
+	return desc
+}
 
 // FilterValue returns the value to filter on.
 func (e Example) FilterValue() string { return e.FileName }
@@ -73,15 +130,37 @@ func (e Example) HaveExtraFile() bool { return e.ExtraFileExist }
 // HaveSecretFile returns true if the example has a secret file.
 func (e Example) HaveSecretFile() bool { return e.SecretFileExist }
 
+// IsDir returns true if the item is a directory of examples.
+func (e Example) IsDir() bool { return e.Directory }
+
 // HaveDependenciesFiles returns true if the example has dependencies files.
 func (e Example) HaveDependenciesFiles() bool { return len(e.DependenciesFiles) > 0 }
 
+// ExampleName returns the testing.upbound.io/example-name label of the resource.
+func (r Resource) ExampleName() string { return r.Metadata.Labels[LabelExampleName] }
+
+// Kinds returns the kinds of the resources of the example, without duplicates.
+func (e Example) Kinds() []string {
+	kinds := []string{}
+	for _, r := range e.Resources {
+		kinds = appendField(kinds, r.Kind)
+	}
+	return kinds
+}
+
 // GetExampleID returns the example ID.
 func (e Example) GetExampleID() string { return e.ExampleID }
 
-// DependenciesFilesList returns a list of dependencies files.
+// DependenciesFilesList returns a list of dependencies files in apply order.
 func (e Example) DependenciesFilesList() []string {
 	list := []string{}
+	if e.DependenciesErr == nil {
+		for _, layer := range e.DependenciesLayers {
+			list = append(list, layer...)
+		}
+		return list
+	}
+
 	for k := range e.DependenciesFiles {
 		list = append(list, k)
 	}
@@ -90,179 +169,103 @@ func (e Example) DependenciesFilesList() []string {
 	return list
 }
 
-// FindSelectorsAndRefs returns the selectors and refs of the example.
-func (e *Example) FindSelectorsAndRefs() (map[string]bool, map[string]bool) {
-	mapsSelector := make(map[string]bool)
-	mapsRefs := make(map[string]bool)
-
-	for k, v := range e.Spec.ForProvider {
-		switch {
-		case reSelector.MatchString(k):
-			if _, isMap := v.(map[string]interface{}); isMap {
-				m := getSelector(v.(map[string]interface{}))
-				maps.Copy(mapsSelector, m)
-			}
-		case reRefs.MatchString(k):
-			for _, v2 := range v.([]interface{}) {
-				m, isMap := v2.(map[string]interface{})
-				if !isMap {
-					continue
-				}
-
-				if name, ok := m["name"].(string); ok {
-					mapsRefs[name] = true
-				}
-			}
-		default:
-			if _, isArray := v.([]interface{}); isArray {
-				isArrayInterface(v.([]interface{}), mapsSelector, mapsRefs)
-			}
-		}
+// Files returns the files to apply for the example: its dependencies if asked, the example and its sidecars.
+func (e Example) Files(withDependencies bool) []string {
+	files := []string{}
+	for _, layer := range e.FileLayers(withDependencies) {
+		files = append(files, layer...)
 	}
-
-	return mapsSelector, mapsRefs
+	return files
 }
 
-func isArrayInterface(i []interface{}, mapsSelector map[string]bool, mapsRefs map[string]bool) {
-	for _, a := range i {
-		if _, ok := a.(map[string]interface{}); !ok {
-			continue
-		}
-
-		m, isMapI := a.(map[string]interface{})
-
-		if !isMapI {
-			continue
-		}
+// FileLayers returns the files to apply for the example in apply order.
+// The dependencies layers come first if asked, the last layer is the example and its sidecars.
+func (e Example) FileLayers(withDependencies bool) [][]string {
+	files := []string{e.FullPath}
 
-		for k, v := range m {
-			switch {
-			case reSelector.MatchString(k):
-				if _, isMap := v.(map[string]interface{}); isMap {
-					selector := getSelector(v.(map[string]interface{}))
-					maps.Copy(mapsSelector, selector)
-				}
-			case reRefs.MatchString(k):
-				for _, v2 := range v.([]interface{}) {
-					ref, isMap := v2.(map[string]interface{})
-					if !isMap {
-						continue
-					}
-
-					if name, ok := ref["name"].(string); ok {
-						mapsRefs[name] = true
-					}
-				}
-			default:
-				if _, isArray := v.([]interface{}); isArray {
-					isArrayInterface(v.([]interface{}), mapsSelector, mapsRefs)
-				}
-			}
-		}
+	if e.HaveExtraFile() {
+		files = append(files, e.ExtraFile)
 	}
-}
 
-func getSelector(v map[string]interface{}) map[string]bool {
-	m := make(map[string]bool)
-	if _, ok := v["matchLabels"].(map[string]interface{}); !ok {
-		return nil
-	}
-	matchLabels, isMap := v["matchLabels"].(map[string]interface{})
-	if !isMap {
-		return nil
-	}
-	exampleName := matchLabels["testing.upbound.io/example-name"]
-	if exampleName != nil {
-		if _, isString := exampleName.(string); isString {
-			m[exampleName.(string)] = true
-		} else {
-			return nil
-		}
+	if e.HaveSecretFile() {
+		files = append(files, e.SecretFile)
 	}
-	return m
-}
 
-// FindDependencies Find Selector and Ref in all files examples.
-func (e *ExamplesDetails) FindDependencies() {
-	e.FindDependenciesSelector()
-	e.FindDependenciesRefs()
+	layers := [][]string{}
+	if withDependencies {
+		layers = append(layers, e.DependenciesLayers...)
+	}
 
-	e.FindDependenciesSelectorFiles()
-	e.FindDependenciesRefsFiles()
+	return append(layers, files)
 }
 
-// FindDependenciesRefs Find Refs in all dependencies files examples.
-func (e *ExamplesDetails) FindDependenciesRefs() {
-	for _, ex := range *e {
-		for s := range ex.Refs {
-			for _, ex2 := range *e {
-				if s == ex2.Metadata.Name {
-					maps.Copy(ex.Refs, ex2.Refs)
-				}
+// List returns all the examples, sorted by path.
+func (l LoadedExamples) List() []*Example {
+	list := []*Example{}
+	for _, items := range l.Examples {
+		for _, item := range items {
+			if e, ok := item.(*Example); ok && !e.IsDir() {
+				list = append(list, e)
 			}
 		}
 	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].FullPath < list[j].FullPath })
+	return list
 }
 
-// FindDependenciesSelector Find Selectors in all dependencies files examples.
-func (e *ExamplesDetails) FindDependenciesSelector() {
-	for _, ex := range *e {
-		for s := range ex.Selectors {
-			for _, ex2 := range *e {
-				if s == ex2.Metadata.Labels.TestingUpboundIoExampleName {
-					maps.Copy(ex.Selectors, ex2.Selectors)
-				}
-			}
-		}
+// Key returns the key of the list of the directory.
+func (l LoadedExamples) Key(dir string) string {
+	if dir == l.Base {
+		return RootDir
 	}
+	return dir
 }
 
-// FindDependenciesSelectorFiles Find Selector in all files examples.
-func (e *ExamplesDetails) FindDependenciesSelectorFiles() {
-	for _, ex := range *e {
-		for s := range ex.Selectors {
-			for _, ex2 := range *e {
-				if ex2.Metadata.Labels.TestingUpboundIoExampleName == s {
-					ex.DependenciesFiles[ex2.FullPath] = true
-					if haveExtraFile(ex2.FileName) {
-						ex.DependenciesFiles[fmt.Sprintf("%s.extra", ex2.FileName)] = true
-					}
-					if haveSecretFile(ex2.FileName) {
-						ex.DependenciesFiles[fmt.Sprintf("%s.secret", ex2.FileName)] = true
-					}
-				}
-			}
-		}
+// Title returns the title of the list of the directory.
+func (l LoadedExamples) Title(dir string) string {
+	if dir == RootDir || dir == l.Base {
+		return "Choose an example"
 	}
+	return fmt.Sprintf("Choose a kind in %s", RelativeName(l.Base)(dir))
 }
 
-// FindDependenciesRefsFiles Find Refs in all files examples.
-func (e *ExamplesDetails) FindDependenciesRefsFiles() {
+// FindDependencies builds the dependency graph of the examples and sets their dependencies files.
+func (e *ExamplesDetails) FindDependencies() *Graph {
+	g := NewGraph(*e)
 	for _, ex := range *e {
-		for s := range ex.Refs {
-			for _, ex2 := range *e {
-				if ex2.Metadata.Name == s {
-					ex.DependenciesFiles[ex2.FullPath] = true
-					if haveExtraFile(ex2.FileName) {
-						ex.DependenciesFiles[fmt.Sprintf("%s.extra", ex2.FileName)] = true
-					}
-					if haveSecretFile(ex2.FileName) {
-						ex.DependenciesFiles[fmt.Sprintf("%s.secret", ex2.FileName)] = true
-					}
-				}
-			}
-		}
+		g.SetDependencies(ex)
 	}
+	return g
 }
 
-func haveExtraFile(filename string) bool {
-	_, err := os.ReadFile(fmt.Sprintf("%s.extra", filename))
-
-	return err == nil
-}
-
-func haveSecretFile(filename string) bool {
-	_, err := os.ReadFile(fmt.Sprintf("%s.secret", filename))
+// SetDependencies sets the dependencies files and layers of the example from the graph.
+func (g *Graph) SetDependencies(ex *Example) {
+	ex.DependenciesFiles = map[string]bool{}
+	ex.DependenciesLayers = nil
+	ex.DependenciesWarning = ""
 
-	return err == nil
+	layers, cycles, err := g.Layers(ex.FullPath)
+	ex.DependenciesErr = err
+	if err != nil {
+		return
+	}
+	warnings := make([]string, 0, len(cycles))
+	for _, c := range cycles {
+		warnings = append(warnings, c.Error())
+	}
+	ex.DependenciesWarning = strings.Join(warnings, "\n")
+
+	// the last layer is the example itself
+	for _, layer := range layers[:len(layers)-1] {
+		files := []string{}
+		for _, path := range layer {
+			dep, _ := g.Example(path)
+			for _, f := range dep.Files(false) {
+				ex.DependenciesFiles[f] = true
+				files = append(files, f)
+			}
+		}
+		ex.DependenciesLayers = append(ex.DependenciesLayers, files)
+	}
 }
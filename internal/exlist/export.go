@@ -0,0 +1,159 @@
+package exlist
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// GraphFormatDOT is the Graphviz DOT format.
+	GraphFormatDOT = "dot"
+	// GraphFormatMermaid is the Mermaid flowchart format.
+	GraphFormatMermaid = "mermaid"
+	// GraphFormatJSON is the JSON format.
+	GraphFormatJSON = "json"
+)
+
+// GraphFormats are the formats supported by WriteGraph.
+var GraphFormats = []string{GraphFormatDOT, GraphFormatMermaid, GraphFormatJSON}
+
+// GraphNode is a node of an exported graph.
+type GraphNode struct {
+	ID         string   `json:"id"`
+	File       string   `json:"file"`
+	Kind       string   `json:"kind"`
+	Kinds      []string `json:"kinds"`
+	APIVersion string   `json:"apiVersion"`
+	ExampleID  string   `json:"exampleId,omitempty"`
+}
+
+type exportedGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []Edge      `json:"edges"`
+}
+
+// WriteGraph writes the graph of the paths and their dependencies in the format.
+// name returns the name of an example path in the output.
+func (g *Graph) WriteGraph(w io.Writer, format string, name func(string) string, paths ...string) error {
+	reachable := g.Reachable(paths...)
+
+	nodes := make([]GraphNode, 0, len(reachable))
+	for _, p := range reachable {
+		ex := g.nodes[p]
+		nodes = append(nodes, GraphNode{
+			ID:         name(p),
+			File:       p,
+			Kind:       ex.Kind,
+			Kinds:      ex.Kinds(),
+			APIVersion: ex.APIVersion,
+			ExampleID:  ex.Metadata.Annotations.MetaUpboundIoExampleID,
+		})
+	}
+
+	edges := g.Edges(reachable)
+	for i := range edges {
+		edges[i].From = name(edges[i].From)
+		edges[i].To = name(edges[i].To)
+	}
+
+	switch format {
+	case GraphFormatDOT:
+		return writeDOT(w, nodes, edges)
+	case GraphFormatMermaid:
+		return writeMermaid(w, nodes, edges)
+	case GraphFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(exportedGraph{Nodes: nodes, Edges: edges})
+	}
+
+	return fmt.Errorf("unknown graph format %s, expected one of %s", format, strings.Join(GraphFormats, ", "))
+}
+
+func writeDOT(w io.Writer, nodes []GraphNode, edges []Edge) error {
+	var b strings.Builder
+	b.WriteString("digraph bean {\n  rankdir=LR;\n  node [shape=box];\n")
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "  %q [label=%q];\n", n.ID, n.ID+"\n"+strings.Join(n.Kinds, ", "))
+	}
+	for _, e := range edges {
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", e.From, e.To, strings.Join(e.Fields, ", "))
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMermaid(w io.Writer, nodes []GraphNode, edges []Edge) error {
+	ids := make(map[string]string, len(nodes))
+
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for i, n := range nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+		fmt.Fprintf(&b, "  %s[\"%s<br/>%s\"]\n", ids[n.ID], mermaidEscape(n.ID), mermaidEscape(strings.Join(n.Kinds, ", ")))
+	}
+	for _, e := range edges {
+		fmt.Fprintf(&b, "  %s -->|%s| %s\n", ids[e.From], mermaidEscape(strings.Join(e.Fields, ", ")), ids[e.To])
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "|", "#124;").Replace(s)
+}
+
+// Tree renders the dependencies of the example as an ASCII tree.
+// name returns the name of an example path in the tree.
+func (g *Graph) Tree(path string, name func(string) string) string {
+	var b strings.Builder
+	b.WriteString(g.treeNode(path, name))
+	b.WriteString("\n")
+	g.tree(&b, path, "", name, map[string]bool{path: true})
+	return b.String()
+}
+
+func (g *Graph) tree(b *strings.Builder, path, prefix string, name func(string) string, ancestors map[string]bool) {
+	deps := g.DependsOn(path)
+	for i, dep := range deps {
+		branch, next := "├── ", "│   "
+		if i == len(deps)-1 {
+			branch, next = "└── ", "    "
+		}
+
+		fmt.Fprintf(b, "%s%s%s → %s", prefix, branch, strings.Join(g.Paths(path, dep), ", "), g.treeNode(dep, name))
+		if ancestors[dep] {
+			b.WriteString(" (cycle)\n")
+			continue
+		}
+		b.WriteString("\n")
+
+		ancestors[dep] = true
+		g.tree(b, dep, prefix+next, name, ancestors)
+		delete(ancestors, dep)
+	}
+}
+
+func (g *Graph) treeNode(path string, name func(string) string) string {
+	if ex, ok := g.nodes[path]; ok {
+		return fmt.Sprintf("%s (%s)", name(path), strings.Join(ex.Kinds(), ", "))
+	}
+	return name(path)
+}
+
+// RelativeName returns a name function giving the example paths relative to base.
+func RelativeName(base string) func(string) string {
+	return func(path string) string {
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return path
+		}
+		return rel
+	}
+}
@@ -0,0 +1,386 @@
+package exlist
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Graph is the dependency graph of the examples.
+// Edges go from an example to the examples it depends on, nodes are keyed by example path.
+type Graph struct {
+	nodes map[string]*Example
+	edges map[string]map[string][]Reference
+	// ambiguous are the edges of the refs matching an example by name or labels only, they count for the refs
+	// matching no example of their kind.
+	ambiguous map[string]map[string][]Reference
+}
+
+// Edge is a dependency between two examples, with the fields of From that caused it.
+type Edge struct {
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Fields []string `json:"fields"`
+	Paths  []string `json:"paths"`
+	// Ambiguous is true if no ref of From matches a resource of To of its kind.
+	Ambiguous bool `json:"ambiguous,omitempty"`
+}
+
+// CycleError is returned when the dependencies of an example contain a cycle.
+type CycleError struct {
+	Cycle []string
+	// Ambiguous is true if the cycle has ambiguous edges, the cycle is broken by ignoring them.
+	Ambiguous bool
+}
+
+// Error returns the cycle as a path.
+func (e *CycleError) Error() string {
+	if e.Ambiguous {
+		return fmt.Sprintf("ambiguous dependency cycle, ignored: %s", strings.Join(e.Cycle, " → "))
+	}
+	return fmt.Sprintf("dependency cycle: %s", strings.Join(e.Cycle, " → "))
+}
+
+// NewGraph builds the dependency graph from the selectors and refs of the examples.
+func NewGraph(examples ExamplesDetails) *Graph {
+	g := &Graph{
+		nodes:     make(map[string]*Example),
+		edges:     make(map[string]map[string][]Reference),
+		ambiguous: make(map[string]map[string][]Reference),
+	}
+
+	for _, ex := range examples {
+		g.add(ex)
+	}
+
+	for _, ex := range examples {
+		if len(ex.References) == 0 {
+			continue
+		}
+		for _, ex2 := range examples {
+			if ex != ex2 {
+				g.link(ex, ex2)
+			}
+		}
+	}
+
+	return g
+}
+
+func (g *Graph) add(ex *Example) {
+	g.nodes[ex.FullPath] = ex
+	g.edges[ex.FullPath] = make(map[string][]Reference)
+	g.ambiguous[ex.FullPath] = make(map[string][]Reference)
+}
+
+// link adds the edge from an example to another one if a ref or a selector of from matches it.
+func (g *Graph) link(from, to *Example) {
+	for _, ref := range from.References {
+		switch ref.MatchExample(to) {
+		case KindMatch:
+			g.edges[from.FullPath][to.FullPath] = append(g.edges[from.FullPath][to.FullPath], ref)
+		case AmbiguousMatch:
+			g.ambiguous[from.FullPath][to.FullPath] = append(g.ambiguous[from.FullPath][to.FullPath], ref)
+		}
+	}
+}
+
+// refs returns the refs of from that make it depend on to, and true if they only match to ambiguously.
+func (g *Graph) refs(from, to string) ([]Reference, bool) {
+	refs := append([]Reference{}, g.edges[from][to]...)
+	if len(g.ambiguous[from][to]) == 0 {
+		return refs, false
+	}
+
+	matched := map[string]bool{}
+	for _, kindRefs := range g.edges[from] {
+		for _, ref := range kindRefs {
+			matched[ref.key()] = true
+		}
+	}
+	ambiguous := len(refs) == 0
+	for _, ref := range g.ambiguous[from][to] {
+		if !matched[ref.key()] {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, ambiguous && len(refs) > 0
+}
+
+// Update replaces the changed examples in the graph, adds the new ones and removes the deleted ones.
+// Only the edges from and to them are computed again. It returns the paths of the examples whose
+// dependencies may have changed, the changed ones and the ones depending on them, before or after the update.
+func (g *Graph) Update(changed ExamplesDetails, removed []string) []string {
+	touched := make([]string, 0, len(changed)+len(removed))
+	for path := range changed {
+		touched = append(touched, path)
+	}
+	touched = append(touched, removed...)
+
+	affected := g.dependents(touched)
+	for _, path := range touched {
+		delete(g.nodes, path)
+		delete(g.edges, path)
+		delete(g.ambiguous, path)
+		for _, deps := range g.edges {
+			delete(deps, path)
+		}
+		for _, deps := range g.ambiguous {
+			delete(deps, path)
+		}
+	}
+
+	for _, ex := range changed {
+		g.add(ex)
+	}
+	for _, ex := range changed {
+		for path, other := range g.nodes {
+			if other == ex {
+				continue
+			}
+			g.link(ex, other)
+			// the edges between two changed examples are added from both sides
+			if _, ok := changed[path]; !ok {
+				g.link(other, ex)
+			}
+		}
+	}
+	for path := range g.dependents(touched) {
+		affected[path] = true
+	}
+
+	paths := make([]string, 0, len(affected))
+	for path := range affected {
+		if _, ok := g.nodes[path]; ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// dependents returns the paths and the examples depending on them, directly or not.
+func (g *Graph) dependents(paths []string) map[string]bool {
+	reverse := make(map[string][]string)
+	for from := range g.nodes {
+		for _, to := range g.DependsOn(from) {
+			reverse[to] = append(reverse[to], from)
+		}
+	}
+
+	seen := make(map[string]bool, len(paths))
+	queue := append([]string{}, paths...)
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		queue = append(queue, reverse[path]...)
+	}
+	return seen
+}
+
+// Example returns the example of the path.
+func (g *Graph) Example(path string) (*Example, bool) {
+	ex, ok := g.nodes[path]
+	return ex, ok
+}
+
+// DependsOn returns the direct dependencies of the example, sorted by path.
+func (g *Graph) DependsOn(path string) []string {
+	deps := make([]string, 0, len(g.edges[path]))
+	for d := range g.edges[path] {
+		deps = append(deps, d)
+	}
+	for d := range g.ambiguous[path] {
+		if _, ok := g.edges[path][d]; ok {
+			continue
+		}
+		if refs, _ := g.refs(path, d); len(refs) > 0 {
+			deps = append(deps, d)
+		}
+	}
+	sort.Strings(deps)
+	return deps
+}
+
+// Reachable returns the paths and their transitive dependencies, sorted.
+// All the examples are returned when no path is given.
+func (g *Graph) Reachable(paths ...string) []string {
+	seen := make(map[string]bool)
+	if len(paths) == 0 {
+		for p := range g.nodes {
+			seen[p] = true
+		}
+	}
+
+	var visit func(string)
+	visit = func(p string) {
+		if seen[p] {
+			return
+		}
+		seen[p] = true
+		for _, dep := range g.DependsOn(p) {
+			visit(dep)
+		}
+	}
+	for _, p := range paths {
+		visit(p)
+	}
+
+	reachable := make([]string, 0, len(seen))
+	for p := range seen {
+		reachable = append(reachable, p)
+	}
+	sort.Strings(reachable)
+	return reachable
+}
+
+// Edges returns the edges between the paths, sorted.
+func (g *Graph) Edges(paths []string) []Edge {
+	in := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		in[p] = true
+	}
+
+	edges := make([]Edge, 0)
+	for _, from := range paths {
+		for _, to := range g.DependsOn(from) {
+			if in[to] {
+				_, ambiguous := g.refs(from, to)
+				edges = append(edges, Edge{
+					From: from, To: to, Fields: g.Fields(from, to), Paths: g.Paths(from, to), Ambiguous: ambiguous,
+				})
+			}
+		}
+	}
+	return edges
+}
+
+// Fields returns the fields of from that make it depend on to.
+func (g *Graph) Fields(from, to string) []string {
+	fields := []string{}
+	refs, _ := g.refs(from, to)
+	for _, ref := range refs {
+		fields = appendField(fields, ref.Field)
+	}
+	return fields
+}
+
+// Paths returns the paths of the fields of from that make it depend on to.
+func (g *Graph) Paths(from, to string) []string {
+	paths := []string{}
+	refs, _ := g.refs(from, to)
+	for _, ref := range refs {
+		paths = append(paths, ref.Path)
+	}
+	return paths
+}
+
+// appendField appends the field if it is not already in the list.
+func appendField(fields []string, field string) []string {
+	for _, f := range fields {
+		if f == field {
+			return fields
+		}
+	}
+	return append(fields, field)
+}
+
+// Layers returns the example and its transitive dependencies in topological order.
+// Each layer only depends on the previous ones, the last layer is the example itself.
+// A cycle with ambiguous edges is broken by ignoring them, it is returned with the layers.
+func (g *Graph) Layers(path string) ([][]string, []*CycleError, error) {
+	if _, ok := g.nodes[path]; !ok {
+		return nil, nil, fmt.Errorf("unknown example %s", path)
+	}
+
+	heights := make(map[string]int)
+	ignored := make(map[[2]string]bool)
+	broken := []*CycleError{}
+	for {
+		err := g.height(path, heights, ignored, map[string]bool{}, []string{})
+		if err == nil {
+			break
+		}
+		if !g.breakCycle(err, ignored) {
+			return nil, nil, err
+		}
+		broken = append(broken, err)
+		heights = make(map[string]int)
+	}
+
+	depHeights := make([][]string, heights[path])
+	for p, h := range heights {
+		if p == path {
+			continue
+		}
+		depHeights[h] = append(depHeights[h], p)
+	}
+
+	layers := make([][]string, 0, len(depHeights)+1)
+	for _, layer := range depHeights {
+		if len(layer) == 0 {
+			continue
+		}
+		sort.Strings(layer)
+		layers = append(layers, layer)
+	}
+
+	return append(layers, []string{path}), broken, nil
+}
+
+// breakCycle adds the ambiguous edges of the cycle to ignored and marks it ambiguous.
+// It returns false if the cycle has no ambiguous edge.
+func (g *Graph) breakCycle(cycle *CycleError, ignored map[[2]string]bool) bool {
+	for i := 0; i+1 < len(cycle.Cycle); i++ {
+		edge := [2]string{cycle.Cycle[i], cycle.Cycle[i+1]}
+		if _, ambiguous := g.refs(edge[0], edge[1]); ambiguous {
+			ignored[edge] = true
+			cycle.Ambiguous = true
+		}
+	}
+	return cycle.Ambiguous
+}
+
+// height computes the height of the example in the graph, 0 for an example without dependencies.
+// visiting holds the examples of the current path to detect cycles.
+func (g *Graph) height(path string, heights map[string]int, ignored map[[2]string]bool, visiting map[string]bool,
+	stack []string,
+) *CycleError {
+	if _, done := heights[path]; done {
+		return nil
+	}
+
+	stack = append(stack, path)
+	if visiting[path] {
+		start := 0
+		for i, p := range stack {
+			if p == path {
+				start = i
+				break
+			}
+		}
+		return &CycleError{Cycle: stack[start:]}
+	}
+	visiting[path] = true
+
+	h := 0
+	for _, dep := range g.DependsOn(path) {
+		if ignored[[2]string{path, dep}] {
+			continue
+		}
+		if err := g.height(dep, heights, ignored, visiting, stack); err != nil {
+			return err
+		}
+		if heights[dep]+1 > h {
+			h = heights[dep] + 1
+		}
+	}
+
+	visiting[path] = false
+	heights[path] = h
+	return nil
+}
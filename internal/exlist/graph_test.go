@@ -0,0 +1,220 @@
+package exlist
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// example returns the example of a single resource, with its references.
+func example(t *testing.T, path, resource string) *Example {
+	t.Helper()
+	var r Resource
+	if err := yaml.Unmarshal([]byte(resource), &r); err != nil {
+		t.Fatal(err)
+	}
+	e := &Example{FullPath: path, Resource: r, Resources: []Resource{r}}
+	e.References = e.FindReferences()
+	return e
+}
+
+// examples returns the examples keyed by path.
+func examples(t *testing.T, resources map[string]string) ExamplesDetails {
+	t.Helper()
+	details := ExamplesDetails{}
+	for path, resource := range resources {
+		details[path] = example(t, path, resource)
+	}
+	return details
+}
+
+const (
+	vpc = `kind: VPC
+metadata:
+  name: example
+  labels:
+    testing.upbound.io/example-name: example
+`
+	bucket = `kind: Bucket
+metadata:
+  name: example
+`
+	subnet = `kind: Subnet
+metadata:
+  name: example
+spec:
+  forProvider:
+    vpcIdSelector:
+      matchLabels:
+        testing.upbound.io/example-name: example
+`
+	instance = `kind: Instance
+metadata:
+  name: example
+spec:
+  forProvider:
+    subnetIdRef:
+      name: example
+    vpcSecurityGroupIdRefs:
+    - name: example
+`
+	securityGroup = `kind: SecurityGroup
+metadata:
+  name: example
+spec:
+  forProvider:
+    vpcIdRef:
+      name: example
+`
+)
+
+func TestLayers(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources map[string]string
+		path      string
+		want      [][]string
+		cycle     bool
+	}{
+		{
+			name:      "no dependency",
+			resources: map[string]string{"vpc": vpc},
+			path:      "vpc",
+			want:      [][]string{{"vpc"}},
+		},
+		{
+			name:      "ref scoped by the kind of the field",
+			resources: map[string]string{"vpc": vpc, "bucket": bucket, "securitygroup": securityGroup},
+			path:      "securitygroup",
+			want:      [][]string{{"vpc"}, {"securitygroup"}},
+		},
+		{
+			name:      "selector scoped by the kind of the field",
+			resources: map[string]string{"vpc": vpc, "bucket": bucket, "subnet": subnet},
+			path:      "subnet",
+			want:      [][]string{{"vpc"}, {"subnet"}},
+		},
+		{
+			name: "layers",
+			resources: map[string]string{
+				"vpc": vpc, "subnet": subnet, "securitygroup": securityGroup, "instance": instance,
+			},
+			path: "instance",
+			want: [][]string{{"vpc"}, {"securitygroup", "subnet"}, {"instance"}},
+		},
+		{
+			name: "ambiguous ref matching no resource of its kind",
+			resources: map[string]string{
+				"restapi": "kind: RestAPI\nmetadata:\n  name: example\n",
+				"resource": "kind: Resource\nmetadata:\n  name: example\n" +
+					"spec:\n  forProvider:\n    parentIdRef:\n      name: example\n",
+			},
+			path: "resource",
+			want: [][]string{{"restapi"}, {"resource"}},
+		},
+		{
+			name: "cycle",
+			resources: map[string]string{
+				"vpc": "kind: VPC\nmetadata:\n  name: example\nspec:\n  forProvider:\n" +
+					"    subnetIdRef:\n      name: example\n",
+				"subnet": "kind: Subnet\nmetadata:\n  name: example\nspec:\n  forProvider:\n" +
+					"    vpcIdRef:\n      name: example\n",
+			},
+			path:  "subnet",
+			cycle: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGraph(examples(t, tt.resources))
+			layers, cycles, err := g.Layers(tt.path)
+
+			var cycleErr *CycleError
+			if tt.cycle {
+				if !errors.As(err, &cycleErr) {
+					t.Fatalf("Layers() error = %v, want a cycle", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Layers() error = %v", err)
+			}
+			if !reflect.DeepEqual(layers, tt.want) {
+				t.Errorf("Layers() = %v, want %v", layers, tt.want)
+			}
+			if len(cycles) != 0 {
+				t.Errorf("Layers() ambiguous cycles = %v, want none", cycles)
+			}
+		})
+	}
+}
+
+func TestLayersAmbiguousCycle(t *testing.T) {
+	// the parentIdRef of the resource only matches the method by name, the method matches the resource by kind
+	g := NewGraph(examples(t, map[string]string{
+		"resource": "kind: Resource\nmetadata:\n  name: example\nspec:\n  forProvider:\n" +
+			"    parentIdRef:\n      name: example\n",
+		"method": "kind: Method\nmetadata:\n  name: example\nspec:\n  forProvider:\n" +
+			"    resourceIdRef:\n      name: example\n",
+	}))
+
+	for path, want := range map[string][][]string{
+		"method":   {{"resource"}, {"method"}},
+		"resource": {{"resource"}},
+	} {
+		layers, cycles, err := g.Layers(path)
+		if err != nil {
+			t.Fatalf("Layers(%s) error = %v", path, err)
+		}
+		if !reflect.DeepEqual(layers, want) {
+			t.Errorf("Layers(%s) = %v, want %v", path, layers, want)
+		}
+		if len(cycles) != 1 || !cycles[0].Ambiguous {
+			t.Errorf("Layers(%s) ambiguous cycles = %v, want one", path, cycles)
+		}
+	}
+}
+
+func TestEdgesAmbiguous(t *testing.T) {
+	g := NewGraph(examples(t, map[string]string{
+		"vpc": vpc, "bucket": bucket, "securitygroup": securityGroup,
+		"restapi": "kind: RestAPI\nmetadata:\n  name: example\n",
+		"resource": "kind: Resource\nmetadata:\n  name: example\n" +
+			"spec:\n  forProvider:\n    parentIdRef:\n      name: example\n",
+	}))
+	got := g.Edges(g.Reachable())
+
+	// the parentIdRef matches every example named example, none of its kind
+	parent := func(to string) Edge {
+		return Edge{
+			From: "resource", To: to, Fields: []string{"parentIdRef"}, Paths: []string{"forProvider.parentIdRef"},
+			Ambiguous: true,
+		}
+	}
+	want := []Edge{
+		parent("bucket"), parent("restapi"), parent("securitygroup"), parent("vpc"),
+		{From: "securitygroup", To: "vpc", Fields: []string{"vpcIdRef"}, Paths: []string{"forProvider.vpcIdRef"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Edges() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	all := examples(t, map[string]string{
+		"vpc": vpc, "bucket": bucket, "subnet": subnet, "securitygroup": securityGroup, "instance": instance,
+		"resource": "kind: Resource\nmetadata:\n  name: example\n" +
+			"spec:\n  forProvider:\n    parentIdRef:\n      name: example\n",
+	})
+	g := NewGraph(ExamplesDetails{"vpc": all["vpc"], "bucket": all["bucket"], "instance": all["instance"]})
+	g.Update(ExamplesDetails{
+		"subnet": all["subnet"], "securitygroup": all["securitygroup"], "resource": all["resource"],
+	}, nil)
+
+	want := NewGraph(all)
+	if got, want := g.Edges(g.Reachable()), want.Edges(want.Reachable()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Edges() after Update =\n%v\nwant\n%v", got, want)
+	}
+}
@@ -0,0 +1,81 @@
+package exlist
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FrangipaneTeam/bean/internal/crd"
+)
+
+// Impact is a resource of an example broken or changed by new CRDs.
+type Impact struct {
+	File   string
+	Line   int
+	Kind   string
+	Reason string
+}
+
+// Affected returns the resources of the examples using a kind, a version or a field removed or renamed by the diff.
+func Affected(examples []*Example, d crd.Diff) []Impact {
+	impacts := []Impact{}
+	for _, e := range examples {
+		for _, r := range e.Resources {
+			group, version, _ := strings.Cut(r.APIVersion, "/")
+			add := func(reason string) {
+				impacts = append(impacts, Impact{File: e.FullPath, Line: r.Line, Kind: r.Kind, Reason: reason})
+			}
+
+			if d.IsRemoved(group, r.Kind) {
+				add(fmt.Sprintf("kind %s was removed", r.Kind))
+				continue
+			}
+			k := d.Kind(group, r.Kind)
+			if k == nil {
+				continue
+			}
+			if k.IsRemovedVersion(version) {
+				add(fmt.Sprintf("version %s of %s was removed", version, r.Kind))
+				continue
+			}
+			v := k.Version(version)
+			if v == nil {
+				continue
+			}
+
+			set := map[string]bool{}
+			specPaths(r.Spec, "", set)
+			for _, f := range v.RemovedFields {
+				if set[f] {
+					add(fmt.Sprintf("spec.%s was removed", f))
+				}
+			}
+			for _, rename := range v.Renamed {
+				if set[rename.From] {
+					add(fmt.Sprintf("spec.%s was renamed to spec.%s", rename.From, rename.To))
+				}
+			}
+		}
+	}
+	return impacts
+}
+
+// specPaths adds the paths of the fields set in v to set, [] stands for the items of a list.
+func specPaths(v interface{}, path string, set map[string]bool) {
+	if path != "" {
+		set[path] = true
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			specPaths(child, p, set)
+		}
+	case []interface{}:
+		for _, child := range v {
+			specPaths(child, path+"[]", set)
+		}
+	}
+}
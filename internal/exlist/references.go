@@ -0,0 +1,203 @@
+package exlist
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	reRef      = regexp.MustCompile(`^\w+Ref$`)
+	reRefs     = regexp.MustCompile(`^\w+Refs$`)
+	reSelector = regexp.MustCompile(`^\w+Selector$`)
+	// reRefSuffix is the suffix of a ref or a selector after the kind it points at, like IdRef in vpcIdRef.
+	reRefSuffix = regexp.MustCompile(`(Ids?|Arns?|Names?)?(Refs?|Selector)$`)
+
+	// ignoredSpecFields are the crossplane fields of the spec that do not reference other examples.
+	ignoredSpecFields = map[string]bool{
+		"providerConfigRef":          true,
+		"writeConnectionSecretToRef": true,
+		"publishConnectionDetailsTo": true,
+	}
+)
+
+// Reference is a ref or a selector found in the spec of an example.
+type Reference struct {
+	// Document is the index of the resource in the example file.
+	Document int
+	// Path is the path of the field in the spec, like forProvider.network[0].subnetIdRef.
+	Path string
+	// Field is the name of the field, like subnetIdRef.
+	Field string
+	// Name is the name of the referenced resource, for refs.
+	Name string
+	// MatchLabels are the labels of the selected resources, for selectors.
+	MatchLabels map[string]string
+}
+
+// IsSelector returns true if the reference is a selector.
+func (r Reference) IsSelector() bool {
+	return r.MatchLabels != nil
+}
+
+// RefMatch is how a reference points at a resource.
+type RefMatch int
+
+const (
+	// NoMatch is a resource of another name or other labels.
+	NoMatch RefMatch = iota
+	// AmbiguousMatch is a resource of the name or the labels of the reference, of another kind than the one
+	// of its field. The field may not name the kind, like parentIdRef, or the name be shared by several kinds.
+	AmbiguousMatch
+	// KindMatch is a resource of the name or the labels and of the kind of the reference.
+	KindMatch
+)
+
+// Kind returns the kind named by the field, lower case: vpc for vpcIdRef, kmskey for kmsKeyIdSelector.
+func (r Reference) Kind() string {
+	return strings.ToLower(reRefSuffix.ReplaceAllString(r.Field, ""))
+}
+
+// IsOfKind returns true if the kind is the one named by the field, when one ends with the other:
+// kmsKeyIdRef points at a Key and targetGroupArnRef at a LBTargetGroup.
+func (r Reference) IsOfKind(kind string) bool {
+	field, kind := r.Kind(), strings.ToLower(kind)
+	return field != "" && kind != "" && (strings.HasSuffix(field, kind) || strings.HasSuffix(kind, field))
+}
+
+// Match returns how the reference points at the resource.
+func (r Reference) Match(res Resource) RefMatch {
+	switch {
+	case !r.matchesMetadata(res):
+		return NoMatch
+	case r.IsOfKind(res.Kind):
+		return KindMatch
+	}
+	return AmbiguousMatch
+}
+
+// matchesMetadata returns true if the name or the labels of the resource are the ones of the reference.
+func (r Reference) matchesMetadata(res Resource) bool {
+	if !r.IsSelector() {
+		return r.Name != "" && r.Name == res.Metadata.Name
+	}
+
+	if len(r.MatchLabels) == 0 {
+		return false
+	}
+	for k, v := range r.MatchLabels {
+		if res.Metadata.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchExample returns the best match of the reference among the resources of the example.
+func (r Reference) MatchExample(e *Example) RefMatch {
+	best := NoMatch
+	for _, res := range e.Resources {
+		if m := r.Match(res); m > best {
+			best = m
+		}
+	}
+	return best
+}
+
+// key identifies the reference in its example.
+func (r Reference) key() string {
+	return fmt.Sprintf("%d/%s", r.Document, r.Path)
+}
+
+// FindReferences returns the refs and the selectors found at any depth of the spec of all the resources of the example.
+func (e *Example) FindReferences() []Reference {
+	refs := make([]Reference, 0)
+	for i, res := range e.Resources {
+		for _, ref := range res.FindReferences() {
+			ref.Document = i
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// FindReferences returns the refs and the selectors found at any depth of the spec of the resource.
+func (r Resource) FindReferences() []Reference {
+	refs := make([]Reference, 0)
+	for _, k := range sortedKeys(r.Spec) {
+		if ignoredSpecFields[k] {
+			continue
+		}
+		refs = walkReferences(k, k, r.Spec[k], refs)
+	}
+	return refs
+}
+
+func walkReferences(path, field string, v interface{}, refs []Reference) []Reference {
+	switch {
+	case reRef.MatchString(field):
+		if m, isMap := v.(map[string]interface{}); isMap {
+			if name, ok := m["name"].(string); ok {
+				return append(refs, Reference{Path: path, Field: field, Name: name})
+			}
+		}
+	case reRefs.MatchString(field):
+		if a, isArray := v.([]interface{}); isArray {
+			for i, item := range a {
+				m, isMap := item.(map[string]interface{})
+				if !isMap {
+					continue
+				}
+				if name, ok := m["name"].(string); ok {
+					refs = append(refs, Reference{Path: fmt.Sprintf("%s[%d]", path, i), Field: field, Name: name})
+				}
+			}
+			return refs
+		}
+	case reSelector.MatchString(field):
+		if m, isMap := v.(map[string]interface{}); isMap {
+			if labels := matchLabels(m); labels != nil {
+				return append(refs, Reference{Path: path, Field: field, MatchLabels: labels})
+			}
+		}
+	}
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(value) {
+			refs = walkReferences(path+"."+k, k, value[k], refs)
+		}
+	case []interface{}:
+		for i, item := range value {
+			refs = walkReferences(fmt.Sprintf("%s[%d]", path, i), field, item, refs)
+		}
+	}
+
+	return refs
+}
+
+// matchLabels returns the string labels of the matchLabels of a selector.
+func matchLabels(selector map[string]interface{}) map[string]string {
+	m, isMap := selector["matchLabels"].(map[string]interface{})
+	if !isMap {
+		return nil
+	}
+
+	labels := make(map[string]string, len(m))
+	for k, v := range m {
+		if s, isString := v.(string); isString {
+			labels[k] = s
+		}
+	}
+	return labels
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,88 @@
+package exlist
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Select returns the examples matching the patterns.
+// A pattern is an examples directory, a glob on the example path or an example-id.
+func Select(basePath string, examples []*Example, patterns []string) ([]*Example, error) {
+	selected := make([]*Example, 0)
+	seen := make(map[string]bool)
+
+	for _, pattern := range patterns {
+		found := false
+		for _, e := range examples {
+			if !Match(basePath, e, pattern) {
+				continue
+			}
+			found = true
+			if !seen[e.FullPath] {
+				seen[e.FullPath] = true
+				selected = append(selected, e)
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("no example matches %s", pattern)
+		}
+	}
+
+	return selected, nil
+}
+
+// Resolve returns the example matching the pattern, an example path or example-id.
+// It fails if the pattern matches several examples.
+func Resolve(basePath string, examples []*Example, pattern string) (*Example, error) {
+	selected, err := Select(basePath, examples, []string{pattern})
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) > 1 {
+		names := make([]string, 0, len(selected))
+		for _, e := range selected {
+			names = append(names, RelativeName(basePath)(e.FullPath))
+		}
+		return nil, fmt.Errorf("%s matches %d examples: %s", pattern, len(selected), strings.Join(names, ", "))
+	}
+	return selected[0], nil
+}
+
+// Match returns true if the example matches the pattern.
+func Match(basePath string, e *Example, pattern string) bool {
+	if pattern == e.ExampleID {
+		return true
+	}
+	for _, r := range e.Resources {
+		if pattern == r.Metadata.Annotations.MetaUpboundIoExampleID {
+			return true
+		}
+	}
+
+	pattern = filepath.Clean(pattern)
+	fullPath := filepath.Clean(e.FullPath)
+	candidates := []string{fullPath, e.FileName}
+	if rel, err := filepath.Rel(basePath, fullPath); err == nil {
+		candidates = append(candidates, rel)
+	}
+
+	for _, c := range candidates {
+		// the pattern is a directory containing the example
+		if c == pattern || strings.HasPrefix(c, pattern+string(filepath.Separator)) {
+			return true
+		}
+		// the pattern is the name of a directory containing the example
+		for dir := filepath.Dir(c); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+			if filepath.Base(dir) == pattern {
+				return true
+			}
+		}
+		if ok, _ := filepath.Match(pattern, c); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -27,8 +27,14 @@ type ListKeyMap struct {
 	Help                  key.Binding
 	ShowRessources        key.Binding
 	ShowTested            key.Binding
+	ShowFieldsCoverage    key.Binding
 	ShowDependanciesFiles key.Binding
 	GenerateListTested    key.Binding
+	ShowGraph             key.Binding
+	ShowDiagnostics       key.Binding
+	SwitchProfile         key.Binding
+	Scaffold              key.Binding
+	ShowCRDChanges        key.Binding
 	ActiveShortHelp       []key.Binding
 	ActiveFullHelp        [][]key.Binding
 }
@@ -84,6 +90,10 @@ func NewListKeyMap() *ListKeyMap {
 			key.WithKeys("t"),
 			key.WithHelp("t", "show tested"),
 		),
+		ShowFieldsCoverage: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "fields coverage"),
+		),
 		ShowDependanciesFiles: key.NewBinding(
 			key.WithKeys("D"),
 			key.WithHelp("D", "dependencies"),
@@ -92,6 +102,26 @@ func NewListKeyMap() *ListKeyMap {
 			key.WithKeys("T"),
 			key.WithHelp("T", "generate list tested"),
 		),
+		ShowGraph: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "dependency graph"),
+		),
+		ShowDiagnostics: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "lint issues"),
+		),
+		SwitchProfile: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "switch profile"),
+		),
+		Scaffold: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "scaffold untested"),
+		),
+		ShowCRDChanges: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "crd changes"),
+		),
 		Left: key.NewBinding(
 			key.WithKeys("left"),
 			key.WithHelp("←", "left"),
@@ -131,10 +161,10 @@ func (m ListKeyMap) FullHelp() [][]key.Binding {
 		{m.VpKM.Up, m.VpKM.Down, m.VpKM.PageUp, m.VpKM.PageDown},
 		{m.UpDown, m.LeftRight, m.Back},
 		{m.ListKeyMap.Filter, m.Select},
-		{m.Help, m.Quit},
+		{m.Help, m.Quit, m.SwitchProfile},
 		{m.Apply, m.Delete, m.Print},
-		{m.Get, m.ShowDependanciesFiles},
-		{m.ShowRessources, m.ShowTested, m.GenerateListTested},
+		{m.Get, m.ShowDependanciesFiles, m.ShowGraph, m.ShowDiagnostics, m.ShowCRDChanges},
+		{m.ShowRessources, m.ShowTested, m.ShowFieldsCoverage, m.GenerateListTested, m.Scaffold},
 	}
 }
 
@@ -155,27 +185,49 @@ func (m *ListKeyMap) EnableViewPortKeys() {
 	m.Back.SetEnabled(true)
 }
 
+// EnableCoverageKeys is the set of keys for the fields coverage.
+func (m *ListKeyMap) EnableCoverageKeys() {
+	m.EnableViewPortKeys()
+	m.Scaffold.SetEnabled(true)
+}
+
+// EnableUntestedKeys is the set of keys for the list of untested kinds.
+func (m *ListKeyMap) EnableUntestedKeys() {
+	m.disableK8SKeys()
+	m.disableViewPortKeys()
+	m.disableList()
+	m.disableMD()
+	m.UpDown.SetEnabled(true)
+	m.Select.SetEnabled(true)
+	m.Back.SetEnabled(true)
+	m.Help.SetEnabled(true)
+}
+
 func (m *ListKeyMap) disableList() {
 	m.UpDown.SetEnabled(false)
 	m.LeftRight.SetEnabled(false)
 	m.ListKeyMap.Filter.SetEnabled(false)
+	m.SwitchProfile.SetEnabled(false)
 }
 
 func (m *ListKeyMap) enableList() {
 	m.UpDown.SetEnabled(true)
 	m.LeftRight.SetEnabled(true)
 	m.ListKeyMap.Filter.SetEnabled(true)
+	m.SwitchProfile.SetEnabled(true)
 }
 
 func (m *ListKeyMap) disableMD() {
 	m.ShowRessources.SetEnabled(false)
 	m.ShowTested.SetEnabled(false)
+	m.ShowFieldsCoverage.SetEnabled(false)
 	m.GenerateListTested.SetEnabled(false)
 }
 
 func (m *ListKeyMap) enableMD() {
 	m.ShowRessources.SetEnabled(true)
 	m.ShowTested.SetEnabled(true)
+	m.ShowFieldsCoverage.SetEnabled(true)
 	m.GenerateListTested.SetEnabled(true)
 }
 
@@ -194,6 +246,9 @@ func (m *ListKeyMap) enableK8SKeys() {
 	m.Print.SetEnabled(true)
 	m.Get.SetEnabled(true)
 	m.ShowDependanciesFiles.SetEnabled(true)
+	m.ShowGraph.SetEnabled(true)
+	m.ShowDiagnostics.SetEnabled(true)
+	m.ShowCRDChanges.SetEnabled(true)
 	m.disableMD()
 	m.enableList()
 }
@@ -204,6 +259,10 @@ func (m *ListKeyMap) disableK8SKeys() {
 	m.Print.SetEnabled(false)
 	m.Get.SetEnabled(false)
 	m.ShowDependanciesFiles.SetEnabled(false)
+	m.ShowGraph.SetEnabled(false)
+	m.ShowDiagnostics.SetEnabled(false)
+	m.ShowCRDChanges.SetEnabled(false)
+	m.Scaffold.SetEnabled(false)
 }
 
 // EnableRootKeys is the set of keys for the root.
@@ -212,6 +271,8 @@ func (m *ListKeyMap) EnableRootKeys() {
 	m.disableViewPortKeys()
 	m.enableList()
 	m.enableMD()
+	m.ShowDiagnostics.SetEnabled(true)
+	m.ShowCRDChanges.SetEnabled(true)
 	m.Back.SetEnabled(false)
 	m.Select.SetEnabled(true)
 	m.Get.SetEnabled(true)
@@ -225,7 +286,7 @@ func (m *ListKeyMap) EnableKindListKeys() {
 	m.Help.SetEnabled(true)
 	m.ListKeyMap.Filter.SetEnabled(true)
 	m.Back.SetEnabled(false)
-	m.Select.SetEnabled(false)
+	m.Select.SetEnabled(true)
 	m.UpDown.SetEnabled(true)
 	m.LeftRight.SetEnabled(true)
 	m.Back.SetEnabled(true)
@@ -0,0 +1,211 @@
+// Package kube provides an in-process kubernetes backend used to apply, delete and list examples.
+package kube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// FieldManager is the field manager used for server-side apply.
+	FieldManager = "bean"
+
+	defaultNamespace = "default"
+)
+
+// Client is a kubernetes client using a dynamic client and a discovery rest mapper.
+type Client struct {
+	dynamic   dynamic.Interface
+	discovery discovery.CachedDiscoveryInterface
+	mapper    *restmapper.DeferredDiscoveryRESTMapper
+
+	// mu guards read, switched by a profile change while a command runs.
+	mu   sync.Mutex
+	read FileReader
+}
+
+// New returns a new client for the given rest config.
+func New(cfg *rest.Config) (*Client, error) {
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	dyn, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := memory.NewMemCacheClient(dc)
+
+	return &Client{
+		dynamic:   dyn,
+		discovery: cached,
+		mapper:    restmapper.NewDeferredDiscoveryRESTMapper(cached),
+	}, nil
+}
+
+// NewFromKubeconfig returns a new client using the kubeconfig file and context.
+// Empty values fall back to the kubectl defaults ($KUBECONFIG, ~/.kube/config and the current context).
+func NewFromKubeconfig(kubeconfig, context string) (*Client, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		rules.ExplicitPath = kubeconfig
+	}
+
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: context}
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return New(cfg)
+}
+
+// CurrentContext returns the name of the context used by NewFromKubeconfig, empty if the kubeconfig can't be read.
+func CurrentContext(kubeconfig, context string) string {
+	if context != "" {
+		return context
+	}
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		rules.ExplicitPath = kubeconfig
+	}
+	raw, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return ""
+	}
+	return raw.CurrentContext
+}
+
+// SetFileReader sets the reader of the files, to decrypt them in memory.
+func (c *Client) SetFileReader(read FileReader) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.read = read
+}
+
+// fileReader returns the reader of the files of the next command.
+func (c *Client) fileReader() FileReader {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.read
+}
+
+// Apply applies the objects found in files with server-side apply.
+func (c *Client) Apply(ctx context.Context, files []string) ([]*unstructured.Unstructured, error) {
+	objs, err := readObjects(c.fileReader(), files)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, o := range objs {
+		obj := o.Unstructured
+		ri, errResource := c.resourceFor(obj)
+		if errResource != nil {
+			return applied, errResource
+		}
+
+		res, errApply := ri.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{
+			FieldManager: FieldManager,
+			Force:        true,
+		})
+		if errApply != nil {
+			return applied, fmt.Errorf("apply %s: %w", objectRef(obj), redact(o, errApply))
+		}
+		applied = append(applied, res)
+	}
+
+	return applied, nil
+}
+
+// Delete deletes the objects found in files without waiting for them to be gone.
+// Objects that do not exist are ignored.
+func (c *Client) Delete(ctx context.Context, files []string) ([]*unstructured.Unstructured, error) {
+	objs, err := ReadObjects(c.fileReader(), files)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := metav1.DeletePropagationBackground
+	deleted := make([]*unstructured.Unstructured, 0, len(objs))
+	var errs []error
+	for _, obj := range objs {
+		ri, errResource := c.resourceFor(obj)
+		if errResource != nil {
+			errs = append(errs, errResource)
+			continue
+		}
+
+		errDelete := ri.Delete(ctx, obj.GetName(), metav1.DeleteOptions{PropagationPolicy: &policy})
+		switch {
+		case apierrors.IsNotFound(errDelete):
+			continue
+		case errDelete != nil:
+			errs = append(errs, fmt.Errorf("delete %s: %w", objectRef(obj), errDelete))
+			continue
+		}
+		deleted = append(deleted, obj)
+	}
+
+	return deleted, errors.Join(errs...)
+}
+
+// Get returns the current state of the object.
+func (c *Client) Get(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	ri, err := c.resourceFor(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+}
+
+// resourceFor returns the dynamic resource interface of the object, defaulting the namespace if needed.
+func (c *Client) resourceFor(obj *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	gvk := obj.GroupVersionKind()
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		// a CRD may have been installed since the last discovery
+		c.mapper.Reset()
+		mapping, err = c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("no resource found for %s: %w", objectRef(obj), err)
+	}
+
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return c.dynamic.Resource(mapping.Resource), nil
+	}
+
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(defaultNamespace)
+	}
+	return c.dynamic.Resource(mapping.Resource).Namespace(obj.GetNamespace()), nil
+}
+
+// redact hides the message of the error of a decrypted object, it may quote its values.
+func redact(o object, err error) error {
+	if !o.sensitive {
+		return err
+	}
+	return fmt.Errorf("%s (details hidden, the object was decrypted)", apierrors.ReasonForError(err))
+}
+
+func objectRef(obj *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName())
+}
@@ -0,0 +1,224 @@
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	ec2Group   = "ec2.aws.upbound.io"
+	ec2Version = ec2Group + "/v1beta1"
+
+	vpc = `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: VPC
+metadata:
+  name: vpc
+spec:
+  forProvider:
+    region: us-west-1
+`
+	secret = `apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+stringData:
+  key: value
+`
+)
+
+// apiServer is a fake API server serving the discovery of the secrets and, once installed, of the VPCs.
+// It stores the objects applied by their path.
+type apiServer struct {
+	t *testing.T
+
+	mu         sync.Mutex
+	vpcs       bool
+	objects    map[string]map[string]interface{}
+	applyQuery []string
+}
+
+func newAPIServer(t *testing.T, vpcs bool) (*apiServer, *Client) {
+	t.Helper()
+	s := &apiServer{t: t, vpcs: vpcs, objects: map[string]map[string]interface{}{}}
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+
+	c, err := New(&rest.Config{Host: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, c
+}
+
+// installVPCs adds the VPCs to the discovery, as a CRD installed after it was cached.
+func (s *apiServer) installVPCs() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.vpcs = true
+}
+
+func (s *apiServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d, ok := s.discovery()[r.URL.Path]; ok {
+		s.write(w, http.StatusOK, d)
+		return
+	}
+
+	obj, exists := s.objects[r.URL.Path]
+	switch {
+	case r.Method == http.MethodPatch:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			s.t.Error(err)
+		}
+		obj = map[string]interface{}{}
+		if err = json.Unmarshal(body, &obj); err != nil {
+			s.t.Error(err)
+		}
+		s.objects[r.URL.Path] = obj
+		s.applyQuery = append(s.applyQuery, r.Header.Get("Content-Type")+" "+r.URL.RawQuery)
+		s.write(w, http.StatusOK, obj)
+	case !exists:
+		name := filepath.Base(r.URL.Path)
+		s.write(w, http.StatusNotFound, apierrors.NewNotFound(schema.GroupResource{}, name).ErrStatus)
+	case r.Method == http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		s.write(w, http.StatusOK, metav1.Status{Status: metav1.StatusSuccess})
+	default:
+		s.write(w, http.StatusOK, obj)
+	}
+}
+
+// discovery returns the discovery documents by path.
+func (s *apiServer) discovery() map[string]interface{} {
+	groups := metav1.APIGroupList{Groups: []metav1.APIGroup{}}
+	if s.vpcs {
+		v := metav1.GroupVersionForDiscovery{GroupVersion: ec2Version, Version: "v1beta1"}
+		groups.Groups = append(groups.Groups, metav1.APIGroup{
+			Name: ec2Group, Versions: []metav1.GroupVersionForDiscovery{v}, PreferredVersion: v,
+		})
+	}
+	verbs := metav1.Verbs{"get", "list", "patch", "delete"}
+	return map[string]interface{}{
+		"/api": metav1.APIVersions{Versions: []string{"v1"}},
+		"/api/v1": metav1.APIResourceList{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "secrets", Kind: "Secret", Namespaced: true, Verbs: verbs},
+		}},
+		"/apis": groups,
+		"/apis/" + ec2Version: metav1.APIResourceList{GroupVersion: ec2Version, APIResources: []metav1.APIResource{
+			{Name: "vpcs", Kind: "VPC", Verbs: verbs},
+		}},
+	}
+}
+
+func (s *apiServer) write(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.t.Error(err)
+	}
+}
+
+// paths returns the sorted paths of the objects stored by the server.
+func (s *apiServer) paths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	paths := []string{}
+	for p := range s.objects {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// writeFile writes a file in a temporary directory and returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestClientApplyGetDelete(t *testing.T) {
+	s, c := newAPIServer(t, true)
+	ctx := context.Background()
+	files := []string{writeFile(t, "example.yaml", vpc+"---\n"+secret)}
+
+	applied, err := c.Apply(ctx, files)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if len(applied) != 2 || applied[1].GetNamespace() != defaultNamespace {
+		t.Fatalf("Apply() = %v, want the VPC and the secret of the default namespace", applied)
+	}
+	wantPaths := []string{"/api/v1/namespaces/default/secrets/creds", "/apis/ec2.aws.upbound.io/v1beta1/vpcs/vpc"}
+	if got := s.paths(); !reflect.DeepEqual(got, wantPaths) {
+		t.Errorf("Apply() stored %v, want %v", got, wantPaths)
+	}
+	for _, q := range s.applyQuery {
+		if q != "application/apply-patch+yaml fieldManager=bean&force=true" {
+			t.Errorf("Apply() request = %q, want a forced server-side apply of bean", q)
+		}
+	}
+
+	got, err := c.Get(ctx, applied[0])
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if region, _, _ := unstructured.NestedString(got.Object, "spec", "forProvider", "region"); region != "us-west-1" {
+		t.Errorf("Get() region = %q, want us-west-1", region)
+	}
+
+	deleted, err := c.Delete(ctx, files)
+	if err != nil || len(deleted) != 2 {
+		t.Fatalf("Delete() = %d objects, %v, want 2", len(deleted), err)
+	}
+	if _, err = c.Get(ctx, applied[0]); !apierrors.IsNotFound(err) {
+		t.Errorf("Get() after Delete() error = %v, want not found", err)
+	}
+
+	// objects already gone are ignored
+	deleted, err = c.Delete(ctx, files)
+	if err != nil || len(deleted) != 0 {
+		t.Errorf("Delete() of deleted objects = %d objects, %v, want none", len(deleted), err)
+	}
+}
+
+func TestClientResourceFor(t *testing.T) {
+	s, c := newAPIServer(t, false)
+	ctx := context.Background()
+
+	// the discovery is cached without the VPCs
+	if _, err := c.Apply(ctx, []string{writeFile(t, "secret.yaml", secret)}); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	_, err := c.Apply(ctx, []string{writeFile(t, "vpc.yaml", vpc)})
+	if err == nil || !strings.HasPrefix(err.Error(), "no resource found for VPC/vpc") {
+		t.Errorf("Apply() of an unknown kind error = %v, want no resource found", err)
+	}
+
+	s.installVPCs()
+	if _, err = c.Apply(ctx, []string{writeFile(t, "vpc.yaml", vpc)}); err != nil {
+		t.Errorf("Apply() after the CRD install error = %v", err)
+	}
+}
@@ -0,0 +1,21 @@
+package kube
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Executor runs the kubernetes actions of bean against a cluster.
+type Executor interface {
+	// Apply applies the objects found in files.
+	Apply(ctx context.Context, files []string) ([]*unstructured.Unstructured, error)
+	// Delete deletes the objects found in files.
+	Delete(ctx context.Context, files []string) ([]*unstructured.Unstructured, error)
+	// Get returns the current state of the object.
+	Get(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	// Managed lists the managed resources.
+	Managed(ctx context.Context) ([]*unstructured.Unstructured, error)
+}
+
+var _ Executor = &Client{}
@@ -0,0 +1,73 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ApplyLayers applies the layers of files in order.
+// Before applying the next layer, it waits for the managed resources of the previous one to be Ready and Synced.
+// It does not wait for the last layer.
+func ApplyLayers(
+	ctx context.Context,
+	e Executor,
+	layers [][]string,
+	timeout, interval time.Duration,
+) ([]*unstructured.Unstructured, error) {
+	applied := make([]*unstructured.Unstructured, 0)
+	for i, layer := range layers {
+		objs, err := e.Apply(ctx, layer)
+		applied = append(applied, objs...)
+		if err != nil {
+			return applied, err
+		}
+
+		if i == len(layers)-1 {
+			break
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, timeout)
+		_, err = WaitReady(waitCtx, e, objs, interval)
+		cancel()
+		if err != nil {
+			return applied, fmt.Errorf("dependencies layer %d: %w", i+1, err)
+		}
+	}
+
+	return applied, nil
+}
+
+// DeleteLayers deletes the layers of files in reverse order.
+// Before deleting the previous layer, it waits for the resources of the current one to be gone.
+// It does not wait for the first layer.
+func DeleteLayers(
+	ctx context.Context,
+	e Executor,
+	layers [][]string,
+	timeout, interval time.Duration,
+) ([]*unstructured.Unstructured, error) {
+	deleted := make([]*unstructured.Unstructured, 0)
+	for i := len(layers) - 1; i >= 0; i-- {
+		objs, err := e.Delete(ctx, layers[i])
+		deleted = append(deleted, objs...)
+		if err != nil {
+			return deleted, err
+		}
+
+		if i == 0 {
+			break
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, timeout)
+		err = WaitDeleted(waitCtx, e, objs, interval)
+		cancel()
+		if err != nil {
+			return deleted, fmt.Errorf("layer %d: %w", i+1, err)
+		}
+	}
+
+	return deleted, nil
+}
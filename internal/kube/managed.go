@@ -0,0 +1,108 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/discovery"
+)
+
+const (
+	// ManagedCategory is the category of the crossplane managed resources.
+	ManagedCategory = "managed"
+
+	// ConditionReady is the ready condition of a managed resource.
+	ConditionReady = "Ready"
+	// ConditionSynced is the synced condition of a managed resource.
+	ConditionSynced = "Synced"
+
+	tabPadding = 3
+)
+
+// Managed lists all the resources of the managed category, like kubectl get managed.
+func (c *Client) Managed(ctx context.Context) ([]*unstructured.Unstructured, error) {
+	lists, err := c.discovery.ServerPreferredResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, err
+	}
+
+	objs := make([]*unstructured.Unstructured, 0)
+	for _, l := range lists {
+		gv, errParse := schema.ParseGroupVersion(l.GroupVersion)
+		if errParse != nil {
+			continue
+		}
+
+		for _, r := range l.APIResources {
+			if !slices.Contains(r.Categories, ManagedCategory) || !slices.Contains(r.Verbs, "list") {
+				continue
+			}
+
+			list, errList := c.dynamic.Resource(gv.WithResource(r.Name)).List(ctx, metav1.ListOptions{})
+			if errList != nil {
+				return nil, fmt.Errorf("list %s: %w", r.Name, errList)
+			}
+			for i := range list.Items {
+				objs = append(objs, &list.Items[i])
+			}
+		}
+	}
+
+	return objs, nil
+}
+
+// Condition returns the status and the message of the condition type of the object.
+func Condition(obj *unstructured.Unstructured, conditionType string) (string, string) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		m, ok := c.(map[string]interface{})
+		if !ok || m["type"] != conditionType {
+			continue
+		}
+
+		status, _ := m["status"].(string)
+		message, _ := m["message"].(string)
+		return status, message
+	}
+
+	return "", ""
+}
+
+// FormatManaged renders the objects as a kubectl get managed table.
+func FormatManaged(objs []*unstructured.Unstructured) string {
+	if len(objs) == 0 {
+		return "No resources found"
+	}
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, tabPadding, ' ', 0)
+	fmt.Fprintln(w, "NAME\tREADY\tSYNCED\tEXTERNAL-NAME\tAGE")
+
+	for _, obj := range objs {
+		ready, _ := Condition(obj, ConditionReady)
+		synced, _ := Condition(obj, ConditionSynced)
+		externalName := obj.GetAnnotations()["crossplane.io/external-name"]
+		age := duration.HumanDuration(time.Since(obj.GetCreationTimestamp().Time))
+
+		fmt.Fprintf(w, "%s.%s/%s\t%s\t%s\t%s\t%s\n",
+			strings.ToLower(obj.GetKind()),
+			obj.GroupVersionKind().Group,
+			obj.GetName(),
+			ready,
+			synced,
+			externalName,
+			age,
+		)
+	}
+	w.Flush()
+
+	return b.String()
+}
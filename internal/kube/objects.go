@@ -0,0 +1,103 @@
+package kube
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const decoderBufferSize = 4096
+
+// FileReader reads an example file, decrypting it in memory if needed.
+// sensitive is true if the content was decrypted: it must not be written to disk or shown.
+type FileReader func(path string) (data []byte, sensitive bool, err error)
+
+// ReadFile is the FileReader of the plain files.
+func ReadFile(path string) ([]byte, bool, error) {
+	data, err := os.ReadFile(path)
+	return data, false, err
+}
+
+// object is an object read from a file.
+type object struct {
+	*unstructured.Unstructured
+	// sensitive is true if the object comes from a decrypted file.
+	sensitive bool
+}
+
+// ReadObjects reads all the kubernetes objects of the yaml files with read, os.ReadFile if nil.
+func ReadObjects(read FileReader, files []string) ([]*unstructured.Unstructured, error) {
+	objs, err := readObjects(read, files)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, o := range objs {
+		list = append(list, o.Unstructured)
+	}
+	return list, nil
+}
+
+func readObjects(read FileReader, files []string) ([]object, error) {
+	if read == nil {
+		read = ReadFile
+	}
+
+	objs := make([]object, 0)
+	for _, file := range files {
+		data, sensitive, err := read(file)
+		if err != nil {
+			return nil, err
+		}
+
+		o, err := DecodeObjects(data)
+		if err != nil {
+			if sensitive {
+				// the decoding error may quote the decrypted content
+				return nil, fmt.Errorf("%s: could not decode the decrypted content", file)
+			}
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		for _, u := range o {
+			objs = append(objs, object{Unstructured: u, sensitive: sensitive})
+		}
+	}
+
+	return objs, nil
+}
+
+// DecodeObjects decodes all the kubernetes objects of a multi-document yaml.
+func DecodeObjects(data []byte) ([]*unstructured.Unstructured, error) {
+	dec := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), decoderBufferSize)
+
+	objs := make([]*unstructured.Unstructured, 0)
+	for {
+		var m map[string]interface{}
+		err := dec.Decode(&m)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// skip empty documents
+		if len(m) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{Object: m}
+		if obj.GetKind() == "" || obj.GetAPIVersion() == "" {
+			return nil, fmt.Errorf("object %q without kind or apiVersion", obj.GetName())
+		}
+		objs = append(objs, obj)
+	}
+
+	return objs, nil
+}
@@ -0,0 +1,123 @@
+package sim
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/FrangipaneTeam/bean/internal/kube"
+)
+
+const subnet = `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: Subnet
+metadata:
+  name: subnet
+spec:
+  forProvider:
+    vpcIdRef:
+      name: vpc
+`
+
+// recorder records the calls to the cluster with the objects it has at the time of the call.
+type recorder struct {
+	*Cluster
+	calls []string
+}
+
+func (r *recorder) Apply(ctx context.Context, files []string) ([]*unstructured.Unstructured, error) {
+	r.record(ctx, "apply", files)
+	return r.Cluster.Apply(ctx, files)
+}
+
+func (r *recorder) Delete(ctx context.Context, files []string) ([]*unstructured.Unstructured, error) {
+	r.record(ctx, "delete", files)
+	return r.Cluster.Delete(ctx, files)
+}
+
+// record adds the call as "verb files: objects", the objects Ready and Synced are marked as ready.
+func (r *recorder) record(ctx context.Context, verb string, files []string) {
+	call := verb
+	for _, f := range files {
+		call += " " + filepath.Base(f)
+	}
+	call += ":"
+	objs, _ := r.Cluster.Managed(ctx)
+	for _, obj := range objs {
+		s := kube.Status(obj)
+		call += " " + s.String()
+		if s.Done() {
+			call += " ready"
+		}
+	}
+	r.calls = append(r.calls, call)
+}
+
+func TestApplyLayers(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures []Failure
+		want     []string
+		wantErr  string
+	}{
+		{
+			name: "waits for the dependencies, not for the last layer",
+			// the subnet is never ready
+			failures: []Failure{{Kind: "Subnet", Condition: kube.ConditionReady, Message: "creating"}},
+			want:     []string{"apply vpc.yaml:", "apply subnet.yaml: VPC/vpc ready"},
+		},
+		{
+			name:     "dependency not ready",
+			failures: []Failure{{Kind: "VPC", Message: "invalid cidr"}},
+			want:     []string{"apply vpc.yaml:"},
+			wantErr:  "dependencies layer 1: VPC/vpc (Synced: invalid cidr) not ready: context deadline exceeded",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{Cluster: New(Config{
+				Synced:   10 * time.Millisecond,
+				Ready:    20 * time.Millisecond,
+				Failures: tt.failures,
+			})}
+			layers := [][]string{{writeFile(t, "vpc.yaml", vpc)}, {writeFile(t, "subnet.yaml", subnet)}}
+
+			_, err := kube.ApplyLayers(context.Background(), r, layers, 50*time.Millisecond, interval)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("ApplyLayers() error = %v", err)
+			case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+				t.Fatalf("ApplyLayers() error = %v, want %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(r.calls, tt.want) {
+				t.Errorf("ApplyLayers() calls = %q, want %q", r.calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteLayers(t *testing.T) {
+	r := &recorder{Cluster: New(Config{Deleted: 20 * time.Millisecond})}
+	layers := [][]string{{writeFile(t, "vpc.yaml", vpc)}, {writeFile(t, "subnet.yaml", subnet)}}
+	for _, layer := range layers {
+		if _, err := r.Cluster.Apply(context.Background(), layer); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deleted, err := kube.DeleteLayers(context.Background(), r, layers, timeout, interval)
+	if err != nil {
+		t.Fatalf("DeleteLayers() error = %v", err)
+	}
+	if len(deleted) != 2 {
+		t.Errorf("DeleteLayers() deleted %d objects, want 2", len(deleted))
+	}
+	// the vpc is deleted once the subnet is gone
+	want := []string{"delete subnet.yaml: Subnet/subnet ready VPC/vpc ready", "delete vpc.yaml: VPC/vpc ready"}
+	if !reflect.DeepEqual(r.calls, want) {
+		t.Errorf("DeleteLayers() calls = %q, want %q", r.calls, want)
+	}
+}
@@ -0,0 +1,303 @@
+// Package sim provides a simulated cluster that keeps applied objects in memory.
+package sim
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/FrangipaneTeam/bean/internal/kube"
+)
+
+const (
+	defaultLatency = 500 * time.Millisecond
+	defaultSynced  = 2 * time.Second
+	defaultReady   = 8 * time.Second
+	defaultDeleted = 3 * time.Second
+)
+
+// Config is the configuration of the simulated cluster.
+type Config struct {
+	// Latency is the time taken by each call to the cluster.
+	Latency time.Duration `mapstructure:"latency" yaml:"latency"`
+	// Synced is the time after which an applied object becomes Synced.
+	Synced time.Duration `mapstructure:"synced" yaml:"synced"`
+	// Ready is the time after which an applied object becomes Ready.
+	Ready time.Duration `mapstructure:"ready" yaml:"ready"`
+	// Deleted is the time after which a deleted object is gone.
+	Deleted time.Duration `mapstructure:"deleted" yaml:"deleted"`
+	// Failures are the failures to inject.
+	Failures []Failure `mapstructure:"failures" yaml:"failures"`
+}
+
+// Failure is a failure injected on the objects matching Kind and Name.
+// An empty Kind or Name matches everything.
+type Failure struct {
+	Kind string `mapstructure:"kind" yaml:"kind"`
+	Name string `mapstructure:"name" yaml:"name"`
+	// Condition is the failing condition, Synced (default) or Ready.
+	Condition string `mapstructure:"condition" yaml:"condition"`
+	// Apply makes the apply call itself fail.
+	Apply   bool   `mapstructure:"apply" yaml:"apply"`
+	Message string `mapstructure:"message" yaml:"message"`
+}
+
+// Default returns the default configuration of the simulated cluster.
+func Default() Config {
+	return Config{
+		Latency: defaultLatency,
+		Synced:  defaultSynced,
+		Ready:   defaultReady,
+		Deleted: defaultDeleted,
+	}
+}
+
+type object struct {
+	obj       *unstructured.Unstructured
+	appliedAt time.Time
+	deletedAt time.Time
+}
+
+// Cluster is a simulated cluster.
+type Cluster struct {
+	mu      sync.Mutex
+	config  Config
+	objects map[string]*object
+	now     func() time.Time
+	read    kube.FileReader
+}
+
+var _ kube.Executor = &Cluster{}
+
+// New returns a new simulated cluster.
+func New(c Config) *Cluster {
+	return &Cluster{
+		config:  c,
+		objects: make(map[string]*object),
+		now:     time.Now,
+	}
+}
+
+// SetFileReader sets the reader of the files, to decrypt them in memory.
+func (c *Cluster) SetFileReader(read kube.FileReader) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.read = read
+}
+
+// fileReader returns the reader of the files of the next command.
+func (c *Cluster) fileReader() kube.FileReader {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.read
+}
+
+// Apply stores the objects found in files.
+func (c *Cluster) Apply(ctx context.Context, files []string) ([]*unstructured.Unstructured, error) {
+	objs, err := kube.ReadObjects(c.fileReader(), files)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	applied := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		if f := c.failure(obj); f != nil && f.Apply {
+			return applied, fmt.Errorf("apply %s/%s: %s", obj.GetKind(), obj.GetName(), f.Message)
+		}
+
+		k := key(obj)
+		o, exists := c.objects[k]
+		if !exists || !o.deletedAt.IsZero() {
+			o = &object{appliedAt: c.now()}
+			obj.SetCreationTimestamp(metav1.NewTime(o.appliedAt))
+			c.objects[k] = o
+		} else {
+			obj.SetCreationTimestamp(o.obj.GetCreationTimestamp())
+		}
+		o.obj = obj.DeepCopy()
+		applied = append(applied, c.status(o))
+	}
+
+	return applied, nil
+}
+
+// Delete marks the objects found in files as deleted.
+func (c *Cluster) Delete(ctx context.Context, files []string) ([]*unstructured.Unstructured, error) {
+	objs, err := kube.ReadObjects(c.fileReader(), files)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	deleted := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		o, exists := c.objects[key(obj)]
+		if !exists {
+			continue
+		}
+		if o.deletedAt.IsZero() {
+			o.deletedAt = c.now()
+		}
+		deleted = append(deleted, c.status(o))
+	}
+
+	return deleted, nil
+}
+
+// Get returns the current state of the object.
+func (c *Cluster) Get(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gc()
+
+	o, exists := c.objects[key(obj)]
+	if !exists {
+		gvk := obj.GroupVersionKind()
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, obj.GetName())
+	}
+
+	return c.status(o), nil
+}
+
+// Managed lists the objects of the cluster.
+func (c *Cluster) Managed(ctx context.Context) ([]*unstructured.Unstructured, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gc()
+
+	keys := make([]string, 0, len(c.objects))
+	for k := range c.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	objs := make([]*unstructured.Unstructured, 0, len(keys))
+	for _, k := range keys {
+		objs = append(objs, c.status(c.objects[k]))
+	}
+
+	return objs, nil
+}
+
+// wait simulates the latency of the cluster.
+func (c *Cluster) wait(ctx context.Context) error {
+	select {
+	case <-time.After(c.config.Latency):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// gc removes the objects deleted for longer than the configured time.
+func (c *Cluster) gc() {
+	for k, o := range c.objects {
+		if !o.deletedAt.IsZero() && c.now().Sub(o.deletedAt) >= c.config.Deleted {
+			delete(c.objects, k)
+		}
+	}
+}
+
+// status returns a copy of the object with the conditions matching the schedule.
+func (c *Cluster) status(o *object) *unstructured.Unstructured {
+	obj := o.obj.DeepCopy()
+	elapsed := c.now().Sub(o.appliedAt)
+	f := c.failure(obj)
+
+	failing := ""
+	if f != nil && !f.Apply {
+		failing = kube.ConditionSynced
+		if strings.EqualFold(f.Condition, kube.ConditionReady) {
+			failing = kube.ConditionReady
+		}
+	}
+
+	conditions := make([]interface{}, 0)
+	if elapsed >= c.config.Synced {
+		conditions = append(conditions, condition(kube.ConditionSynced, failing, f, "ReconcileSuccess"))
+	}
+	switch {
+	case elapsed >= c.config.Ready && failing != kube.ConditionSynced:
+		conditions = append(conditions, condition(kube.ConditionReady, failing, f, "Available"))
+	case elapsed >= c.config.Synced:
+		conditions = append(conditions, map[string]interface{}{
+			"type":   kube.ConditionReady,
+			"status": string(metav1.ConditionFalse),
+			"reason": "Creating",
+		})
+	}
+
+	if !o.deletedAt.IsZero() {
+		obj.SetDeletionTimestamp(&metav1.Time{Time: o.deletedAt})
+	}
+
+	_ = unstructured.SetNestedSlice(obj.Object, conditions, "status", "conditions")
+	return obj
+}
+
+func condition(conditionType, failing string, f *Failure, reason string) map[string]interface{} {
+	if conditionType == failing {
+		return map[string]interface{}{
+			"type":    conditionType,
+			"status":  string(metav1.ConditionFalse),
+			"reason":  "ReconcileError",
+			"message": f.Message,
+		}
+	}
+
+	return map[string]interface{}{
+		"type":   conditionType,
+		"status": string(metav1.ConditionTrue),
+		"reason": reason,
+	}
+}
+
+// failure returns the first failure matching the object.
+func (c *Cluster) failure(obj *unstructured.Unstructured) *Failure {
+	for i, f := range c.config.Failures {
+		if f.Kind != "" && !strings.EqualFold(f.Kind, obj.GetKind()) {
+			continue
+		}
+		if f.Name != "" && f.Name != obj.GetName() {
+			continue
+		}
+		return &c.config.Failures[i]
+	}
+
+	return nil
+}
+
+func key(obj *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s/%s", obj.GetAPIVersion(), obj.GetKind(), obj.GetName())
+}
@@ -0,0 +1,56 @@
+package sim
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/FrangipaneTeam/bean/internal/kube"
+)
+
+const vpc = `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: VPC
+metadata:
+  name: vpc
+spec:
+  forProvider:
+    region: us-west-1
+`
+
+// writeFile writes a file in a temporary directory and returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// fastConfig is a schedule without latency, the resources are never ready unless set.
+func fastConfig() Config {
+	return Config{Synced: 1 << 62, Ready: 1 << 62, Deleted: 1 << 62}
+}
+
+func TestSetFileReaderDuringApply(t *testing.T) {
+	c := New(fastConfig())
+	file := writeFile(t, "vpc.yaml", vpc)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Apply(context.Background(), []string{file}); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			c.SetFileReader(kube.ReadFile)
+		}()
+	}
+	wg.Wait()
+}
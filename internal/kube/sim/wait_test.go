@@ -0,0 +1,51 @@
+package sim
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FrangipaneTeam/bean/internal/kube"
+)
+
+const (
+	interval = 5 * time.Millisecond
+	timeout  = time.Second
+)
+
+func TestWaitReady(t *testing.T) {
+	c := New(Config{Synced: 10 * time.Millisecond, Ready: 20 * time.Millisecond})
+	objs, err := c.Apply(context.Background(), []string{writeFile(t, "vpc.yaml", vpc)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	statuses, err := kube.WaitReady(ctx, c, objs, interval)
+	if err != nil {
+		t.Fatalf("WaitReady() error = %v", err)
+	}
+	if len(statuses) != 1 || !statuses[0].Done() {
+		t.Errorf("WaitReady() = %v, want VPC/vpc ready", statuses)
+	}
+}
+
+func TestWaitReadyTimeout(t *testing.T) {
+	c := New(Config{Failures: []Failure{{Kind: "VPC", Message: "invalid cidr"}}})
+	objs, err := c.Apply(context.Background(), []string{writeFile(t, "vpc.yaml", vpc)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = kube.WaitReady(ctx, c, objs, interval)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitReady() error = %v, want a deadline exceeded", err)
+	}
+	if want := "VPC/vpc (Synced: invalid cidr) not ready: context deadline exceeded"; err.Error() != want {
+		t.Errorf("WaitReady() error = %q, want %q", err, want)
+	}
+}
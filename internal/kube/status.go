@@ -0,0 +1,126 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ResourceStatus is the readiness of a managed resource.
+type ResourceStatus struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Found  bool   `json:"found"`
+	Ready  bool   `json:"ready"`
+	Synced bool   `json:"synced"`
+	// Message is the message of a false condition. Crossplane reports transient errors until the resource
+	// is ready, an unresolved reference to a resource still being created for instance, so it is progress.
+	Message string `json:"message,omitempty"`
+}
+
+// IsManaged returns true if the object is a crossplane managed resource.
+func IsManaged(obj *unstructured.Unstructured) bool {
+	spec, _, _ := unstructured.NestedMap(obj.Object, "spec")
+	return IsManagedSpec(spec)
+}
+
+// IsManagedSpec returns true if the spec is the one of a crossplane managed resource, with forProvider parameters.
+// The ProviderConfigs and the other kinds of the providers are not managed.
+func IsManagedSpec(spec map[string]interface{}) bool {
+	_, ok := spec["forProvider"].(map[string]interface{})
+	return ok
+}
+
+// ManagedObjects returns the managed resources of the objects.
+func ManagedObjects(objs []*unstructured.Unstructured) []*unstructured.Unstructured {
+	managed := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		if IsManaged(obj) {
+			managed = append(managed, obj)
+		}
+	}
+	return managed
+}
+
+// Status returns the readiness of the object.
+func Status(obj *unstructured.Unstructured) ResourceStatus {
+	s := ResourceStatus{
+		Kind:  obj.GetKind(),
+		Name:  obj.GetName(),
+		Found: true,
+	}
+
+	ready, readyMessage := Condition(obj, ConditionReady)
+	synced, syncedMessage := Condition(obj, ConditionSynced)
+	s.Ready = ready == string(metav1.ConditionTrue)
+	s.Synced = synced == string(metav1.ConditionTrue)
+
+	switch {
+	case synced == string(metav1.ConditionFalse) && syncedMessage != "":
+		s.Message = fmt.Sprintf("%s: %s", ConditionSynced, syncedMessage)
+	case ready == string(metav1.ConditionFalse) && readyMessage != "":
+		s.Message = fmt.Sprintf("%s: %s", ConditionReady, readyMessage)
+	}
+
+	return s
+}
+
+// Statuses returns the readiness of the objects as currently known by the cluster.
+func Statuses(ctx context.Context, e Executor, objs []*unstructured.Unstructured) ([]ResourceStatus, error) {
+	statuses := make([]ResourceStatus, 0, len(objs))
+	for _, obj := range objs {
+		current, err := e.Get(ctx, obj)
+		switch {
+		case apierrors.IsNotFound(err):
+			statuses = append(statuses, ResourceStatus{Kind: obj.GetKind(), Name: obj.GetName()})
+			continue
+		case err != nil:
+			return nil, err
+		}
+		statuses = append(statuses, Status(current))
+	}
+
+	return statuses, nil
+}
+
+// Done returns true if the resource is Ready and Synced.
+func (s ResourceStatus) Done() bool {
+	return s.Ready && s.Synced
+}
+
+// String returns the kind/name of the resource.
+func (s ResourceStatus) String() string {
+	return fmt.Sprintf("%s/%s", s.Kind, s.Name)
+}
+
+// CountDone returns the number of resources Ready and Synced.
+func CountDone(statuses []ResourceStatus) int {
+	n := 0
+	for _, s := range statuses {
+		if s.Done() {
+			n++
+		}
+	}
+	return n
+}
+
+// NotReady returns the first resource not Ready and Synced, if any.
+func NotReady(statuses []ResourceStatus) (ResourceStatus, bool) {
+	for _, s := range statuses {
+		if !s.Done() {
+			return s, true
+		}
+	}
+	return ResourceStatus{}, false
+}
+
+// Pending returns the resource with the message of its false condition, if any.
+func (s ResourceStatus) Pending() string {
+	if s.Message == "" {
+		return s.String()
+	}
+	return fmt.Sprintf("%s (%s)", s, s.Message)
+}
@@ -0,0 +1,75 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// WaitReady waits for the managed resources of objs to be Ready and Synced.
+// A false condition is not a failure, crossplane reports transient errors until the resource is ready:
+// it returns an error with the last message of the resource not ready when the context is done.
+func WaitReady(ctx context.Context, e Executor, objs []*unstructured.Unstructured, interval time.Duration) ([]ResourceStatus, error) {
+	managed := ManagedObjects(objs)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var statuses []ResourceStatus
+	for {
+		current, err := Statuses(ctx, e, managed)
+		if err != nil && ctx.Err() == nil {
+			return statuses, err
+		}
+		if err == nil {
+			statuses = current
+		}
+
+		if err == nil && CountDone(statuses) == len(statuses) {
+			return statuses, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if s, pending := NotReady(statuses); pending {
+				return statuses, fmt.Errorf("%s not ready: %w", s.Pending(), ctx.Err())
+			}
+			return statuses, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// WaitDeleted waits for objs to be gone from the cluster.
+func WaitDeleted(ctx context.Context, e Executor, objs []*unstructured.Unstructured, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		statuses, err := Statuses(ctx, e, objs)
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		remaining := ""
+		for _, s := range statuses {
+			if s.Found {
+				remaining = s.String()
+				break
+			}
+		}
+		if err == nil && remaining == "" {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if remaining != "" {
+				return fmt.Errorf("%s still exists: %w", remaining, ctx.Err())
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
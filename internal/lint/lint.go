@@ -0,0 +1,192 @@
+// Package lint validates the example files against the openAPIV3Schema of their CRDs.
+package lint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/FrangipaneTeam/bean/internal/crd"
+)
+
+// Rule is the name of a check.
+type Rule string
+
+const (
+	// RuleSyntax is a document that is not valid yaml.
+	RuleSyntax Rule = "syntax"
+	// RuleRender is a file that can't be rendered with the variables of the profile.
+	RuleRender Rule = "render"
+	// RuleMissingCRD is a kind or a version without CRD.
+	RuleMissingCRD Rule = "missing-crd"
+	// RuleUnknownField is a field not in the schema.
+	RuleUnknownField Rule = "unknown-field"
+	// RuleType is a value of the wrong type.
+	RuleType Rule = "type"
+	// RuleRequired is a required field that is not set.
+	RuleRequired Rule = "required"
+	// RuleEnum is a value not in the enum of the field.
+	RuleEnum Rule = "enum"
+
+	// RuleExampleID is a meta.upbound.io/example-id annotation missing or not matching the kind of the file.
+	RuleExampleID Rule = "example-id"
+	// RuleDuplicateName is a metadata.name used by several resources of a kind.
+	RuleDuplicateName Rule = "duplicate-name"
+	// RuleDuplicateLabel is a testing.upbound.io/example-name label used by several resources of a kind.
+	RuleDuplicateLabel Rule = "duplicate-label"
+	// RuleDanglingRef is a ref or a selector matching no resource of the examples.
+	RuleDanglingRef Rule = "dangling-ref"
+	// RuleProviderConfig is a managed resource without providerConfigRef.
+	RuleProviderConfig Rule = "provider-config"
+)
+
+// Rules are the descriptions of the rules.
+var Rules = map[Rule]string{
+	RuleSyntax:         "the document is not valid yaml",
+	RuleRender:         "the file can't be rendered with the variables of the profile",
+	RuleMissingCRD:     "no CRD defines the kind or the version",
+	RuleUnknownField:   "the field is not in the schema of the CRD",
+	RuleType:           "the value does not have the type of the field",
+	RuleRequired:       "a required field is not set",
+	RuleEnum:           "the value is not in the enum of the field",
+	RuleExampleID:      "the meta.upbound.io/example-id annotation is missing or does not match the kind of the file",
+	RuleDuplicateName:  "the metadata.name is used by several resources of the kind",
+	RuleDuplicateLabel: "the testing.upbound.io/example-name label is used by several resources of the kind",
+	RuleDanglingRef:    "a ref or a selector matches no resource of the examples",
+	RuleProviderConfig: "the managed resource has no providerConfigRef",
+}
+
+// IsProviderKind returns true if the apiVersion is the one of a kind of a provider, not of kubernetes.
+func IsProviderKind(apiVersion string) bool {
+	group, _, found := strings.Cut(apiVersion, "/")
+	return found && strings.Contains(group, ".") && !strings.HasSuffix(group, ".k8s.io")
+}
+
+// Filter returns the diagnostics whose rule is not disabled.
+func Filter(diags []Diagnostic, disabled []string) []Diagnostic {
+	if len(disabled) == 0 {
+		return diags
+	}
+	off := make(map[Rule]bool, len(disabled))
+	for _, r := range disabled {
+		off[Rule(r)] = true
+	}
+
+	kept := diags[:0]
+	for _, d := range diags {
+		if !off[d.Rule] {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+// Diagnostic is an issue found in an example file.
+type Diagnostic struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Path is the path of the field in the document, empty for the whole document.
+	Path    string `json:"path"`
+	Rule    Rule   `json:"rule"`
+	Message string `json:"message"`
+}
+
+// String returns the diagnostic as file:line: message (rule).
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d: %s (%s)", d.File, d.Line, d.Message, d.Rule)
+}
+
+// Linter validates the documents against the schemas of the CRDs.
+type Linter struct {
+	// crds are keyed by group/kind.
+	crds map[string]crd.CRD
+}
+
+// New returns a linter of the documents of the kinds of the CRDs.
+func New(crds []crd.CRD) *Linter {
+	l := &Linter{crds: make(map[string]crd.CRD, len(crds))}
+	for _, c := range crds {
+		if c.Spec.Group == "" || c.Spec.Names.Kind == "" {
+			continue
+		}
+		l.crds[c.Spec.Group+"/"+c.Spec.Names.Kind] = c
+	}
+	return l
+}
+
+// Lint returns the diagnostics of the documents of data, sorted by line.
+// A nil linter finds nothing.
+func (l *Linter) Lint(file string, data []byte) []Diagnostic {
+	if l == nil {
+		return nil
+	}
+
+	v := &validator{file: file}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		doc := &yaml.Node{}
+		err := dec.Decode(doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			v.add(0, "", RuleSyntax, err.Error())
+			break
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		l.lintDocument(v, doc.Content[0])
+	}
+
+	sort.SliceStable(v.diags, func(i, j int) bool { return v.diags[i].Line < v.diags[j].Line })
+	return v.diags
+}
+
+func (l *Linter) lintDocument(v *validator, doc *yaml.Node) {
+	apiVersion, kind := scalar(doc, "apiVersion"), scalar(doc, "kind")
+	if apiVersion == "" && kind == "" {
+		return
+	}
+
+	if !IsProviderKind(apiVersion) {
+		// a kind of kubernetes, not of a provider
+		return
+	}
+	group, version, _ := strings.Cut(apiVersion, "/")
+
+	c, ok := l.crds[group+"/"+kind]
+	if !ok {
+		v.add(doc.Line, "", RuleMissingCRD, fmt.Sprintf("no CRD for kind %s of group %s", kind, group))
+		return
+	}
+	crdVersion := c.Version(version)
+	if crdVersion == nil {
+		v.add(doc.Line, "apiVersion", RuleMissingCRD,
+			fmt.Sprintf("no version %s of kind %s, expected one of %v", version, kind, c.VersionNames()))
+		return
+	}
+	if crdVersion.Schema.OpenAPIV3Schema == nil {
+		return
+	}
+
+	v.validate(doc, crdVersion.Schema.OpenAPIV3Schema, "")
+}
+
+// scalar returns the value of the key of a mapping.
+func scalar(n *yaml.Node, key string) string {
+	if n.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1].Value
+		}
+	}
+	return ""
+}
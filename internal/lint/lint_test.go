@@ -0,0 +1,154 @@
+package lint
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/FrangipaneTeam/bean/internal/crd"
+)
+
+// testLinter returns the linter of the CRD shared by the tests of the crd package.
+func testLinter(t *testing.T) *Linter {
+	t.Helper()
+	c, err := crd.ReadFile(filepath.Join("..", "crd", "testdata", "ec2.aws.upbound.io_vpcendpoints.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New([]crd.CRD{c})
+}
+
+// issues returns the rule and the path of the diagnostics.
+func issues(diags []Diagnostic) []string {
+	got := []string{}
+	for _, d := range diags {
+		got = append(got, string(d.Rule)+" "+d.Path)
+	}
+	return got
+}
+
+func TestLint(t *testing.T) {
+	l := testLinter(t)
+	tests := []struct {
+		name string
+		yaml string
+		want []string
+	}{
+		{
+			name: "valid",
+			yaml: `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: VPCEndpoint
+metadata:
+  name: example
+spec:
+  forProvider:
+    region: us-west-1
+    serviceName: com.amazonaws.us-west-1.s3
+  providerConfigRef:
+    name: default
+`,
+			want: []string{},
+		},
+		{
+			name: "required parameters of the rules",
+			yaml: `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: VPCEndpoint
+metadata:
+  name: example
+spec:
+  forProvider:
+    vpcId: vpc-1
+`,
+			want: []string{"required spec.forProvider.region", "required spec.forProvider.serviceName"},
+		},
+		{
+			name: "required parameter set in initProvider",
+			yaml: `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: VPCEndpoint
+metadata:
+  name: example
+spec:
+  forProvider:
+    region: us-west-1
+  initProvider:
+    serviceName: com.amazonaws.us-west-1.s3
+`,
+			want: []string{},
+		},
+		{
+			name: "required fields merged",
+			yaml: `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: VPCEndpoint
+metadata:
+  name: example
+spec:
+  forProvider:
+    <<: &endpoint
+      region: us-west-1
+      serviceName: com.amazonaws.us-west-1.s3
+  providerConfigRef:
+    <<: [{name: default}]
+---
+apiVersion: ec2.aws.upbound.io/v1beta1
+kind: VPCEndpoint
+metadata:
+  name: other
+spec:
+  forProvider: *endpoint
+`,
+			want: []string{},
+		},
+		{
+			name: "required parameter missing from the merged mapping",
+			yaml: `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: VPCEndpoint
+metadata:
+  name: example
+spec:
+  forProvider:
+    <<: {region: us-west-1}
+`,
+			want: []string{"required spec.forProvider.serviceName"},
+		},
+		{
+			name: "unknown field and wrong type",
+			yaml: `apiVersion: ec2.aws.upbound.io/v1beta1
+kind: VPCEndpoint
+metadata:
+  name: example
+spec:
+  forProvider:
+    region: us-west-1
+    serviceName: 3
+    vpc: vpc-1
+`,
+			want: []string{"type spec.forProvider.serviceName", "unknown-field spec.forProvider.vpc"},
+		},
+		{
+			name: "missing CRD",
+			yaml: `apiVersion: ec2.aws.upbound.io/v1beta2
+kind: VPCEndpoint
+metadata:
+  name: example
+---
+apiVersion: s3.aws.upbound.io/v1beta1
+kind: Bucket
+metadata:
+  name: example
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: example
+`,
+			want: []string{"missing-crd apiVersion", "missing-crd "},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issues(l.Lint(filepath.Join("examples", "vpcendpoint.yaml"), []byte(tt.yaml))); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
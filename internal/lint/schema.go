@@ -0,0 +1,251 @@
+package lint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/FrangipaneTeam/bean/internal/crd"
+)
+
+// mergeKey is the yaml merge key, its values are checked where they are defined.
+const mergeKey = "<<"
+
+// validator collects the diagnostics of a file.
+type validator struct {
+	file  string
+	diags []Diagnostic
+}
+
+func (v *validator) add(line int, path string, rule Rule, msg string) {
+	v.diags = append(v.diags, Diagnostic{File: v.file, Line: line, Path: path, Rule: rule, Message: msg})
+}
+
+// validate checks the node against the schema, path is the path of the node.
+func (v *validator) validate(n *yaml.Node, s *crd.Schema, path string) {
+	if s == nil || n.Tag == "!!null" {
+		return
+	}
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+
+	switch s.Type {
+	case "object", "":
+		if n.Kind != yaml.MappingNode {
+			if s.Type != "" {
+				v.typeError(n, s, path)
+			}
+			return
+		}
+		v.object(n, s, path)
+
+	case "array":
+		if n.Kind != yaml.SequenceNode {
+			v.typeError(n, s, path)
+			return
+		}
+		for i, item := range n.Content {
+			v.validate(item, s.Items, fmt.Sprintf("%s[%d]", path, i))
+		}
+
+	default:
+		if n.Kind != yaml.ScalarNode || !scalarOfType(n, s) {
+			v.typeError(n, s, path)
+			return
+		}
+		v.enum(n, s, path)
+	}
+}
+
+func (v *validator) object(n *yaml.Node, s *crd.Schema, path string) {
+	set := map[string]bool{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if key.Value == mergeKey {
+			for _, merged := range mergedKeys(value) {
+				set[merged] = true
+			}
+			continue
+		}
+		set[key.Value] = true
+		childPath := join(path, key.Value)
+
+		switch p, ok := s.Properties[key.Value]; {
+		case ok:
+			v.validate(value, p, childPath)
+		case s.AdditionalProperties != nil && s.AdditionalProperties.Allowed:
+			v.validate(value, s.AdditionalProperties.Schema, childPath)
+		case s.PreserveUnknown || (len(s.Properties) == 0 && s.AdditionalProperties == nil):
+			// a free-form object like metadata
+		default:
+			v.add(key.Line, childPath, RuleUnknownField, fmt.Sprintf("unknown field %s", childPath))
+		}
+	}
+
+	for _, r := range s.Required {
+		if !set[r] {
+			v.add(n.Line, join(path, r), RuleRequired, fmt.Sprintf("missing required field %s", join(path, r)))
+		}
+	}
+	for _, p := range s.RequiredParameters() {
+		if !hasAny(n, p.Alternatives) {
+			v.add(n.Line, join(path, p.Path), RuleRequired, fmt.Sprintf("missing required field %s", join(path, p.Path)))
+		}
+	}
+}
+
+// hasAny returns true if one of the dotted paths is set in the mapping.
+func hasAny(n *yaml.Node, paths []string) bool {
+	for _, path := range paths {
+		if has(n, strings.Split(path, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+func has(n *yaml.Node, keys []string) bool {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if len(keys) == 0 {
+		return n.Tag != "!!null"
+	}
+	if v := lookup(n, keys[0]); v != nil {
+		return has(v, keys[1:])
+	}
+	return false
+}
+
+// lookup returns the value of the key in the mapping or in the mappings it merges, nil if it is not set.
+func lookup(n *yaml.Node, key string) *yaml.Node {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value != mergeKey {
+			continue
+		}
+		for _, merged := range mergedMappings(n.Content[i+1]) {
+			if v := lookup(merged, key); v != nil {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// mergedKeys returns the keys of the mappings merged by the value of a merge key, theirs merges included.
+func mergedKeys(value *yaml.Node) []string {
+	keys := []string{}
+	for _, m := range mergedMappings(value) {
+		for i := 0; i+1 < len(m.Content); i += 2 {
+			if m.Content[i].Value == mergeKey {
+				keys = append(keys, mergedKeys(m.Content[i+1])...)
+				continue
+			}
+			keys = append(keys, m.Content[i].Value)
+		}
+	}
+	return keys
+}
+
+// mergedMappings returns the mappings merged by the value of a merge key, a mapping or a list of mappings.
+func mergedMappings(value *yaml.Node) []*yaml.Node {
+	if value.Kind == yaml.AliasNode {
+		value = value.Alias
+	}
+	switch value.Kind {
+	case yaml.MappingNode:
+		return []*yaml.Node{value}
+	case yaml.SequenceNode:
+		mappings := []*yaml.Node{}
+		for _, item := range value.Content {
+			mappings = append(mappings, mergedMappings(item)...)
+		}
+		return mappings
+	}
+	return nil
+}
+
+func (v *validator) enum(n *yaml.Node, s *crd.Schema, path string) {
+	if len(s.Enum) == 0 {
+		return
+	}
+	for _, e := range s.Enum {
+		if fmt.Sprint(e) == n.Value {
+			return
+		}
+	}
+	v.add(n.Line, path, RuleEnum, fmt.Sprintf("%s: %q is not one of %v", path, n.Value, s.Enum))
+}
+
+func (v *validator) typeError(n *yaml.Node, s *crd.Schema, path string) {
+	v.add(n.Line, path, RuleType, fmt.Sprintf("%s: expected %s, got %s", path, typeName(s), nodeType(n)))
+}
+
+// scalarOfType returns true if the scalar node has the type of the schema.
+func scalarOfType(n *yaml.Node, s *crd.Schema) bool {
+	if s.IntOrString && (n.Tag == "!!int" || n.Tag == "!!str") {
+		return true
+	}
+	switch s.Type {
+	case "string":
+		return n.Tag == "!!str" || n.Tag == "!!timestamp" || n.Tag == "!!binary"
+	case "integer":
+		_, err := strconv.ParseInt(n.Value, 0, 64)
+		return n.Tag == "!!int" && err == nil
+	case "number":
+		return n.Tag == "!!int" || n.Tag == "!!float"
+	case "boolean":
+		return n.Tag == "!!bool"
+	}
+	return true
+}
+
+func typeName(s *crd.Schema) string {
+	if s.IntOrString {
+		return "an integer or a string"
+	}
+	switch s.Type {
+	case "object", "array", "integer":
+		return "an " + s.Type
+	}
+	return "a " + s.Type
+}
+
+func nodeType(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "an object"
+	case yaml.SequenceNode:
+		return "an array"
+	}
+	switch n.Tag {
+	case "!!int":
+		return "an integer"
+	case "!!float":
+		return "a number"
+	case "!!bool":
+		return "a boolean"
+	}
+	return "a string"
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
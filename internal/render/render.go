@@ -0,0 +1,82 @@
+// Package render substitutes the variables of a profile in the example files.
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"text/template"
+
+	"github.com/FrangipaneTeam/bean/internal/kube"
+)
+
+// reVariable matches ${VAR} and ${VAR:-default}.
+var reVariable = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_.-]*)(:-([^}]*))?\}`)
+
+// TemplateMarker is the comment line of the files rendered as Go templates. The other files may carry
+// literal {{ }}, as SSM documents or Helm values do.
+const TemplateMarker = "# bean:template"
+
+// reTemplateMarker matches the TemplateMarker line.
+var reTemplateMarker = regexp.MustCompile(`(?m)^#\s*bean:template\s*$`)
+
+// Vars are the variables of a profile.
+type Vars map[string]string
+
+// IsTemplate returns true if the file has the TemplateMarker line.
+func IsTemplate(data []byte) bool {
+	return reTemplateMarker.Match(data)
+}
+
+// Render renders the Go template actions of a file marked by TemplateMarker, then substitutes its ${VAR} variables.
+// A ${VAR} without value nor default is kept as is, a template action without value is an error.
+func (v Vars) Render(name string, data []byte) ([]byte, error) {
+	if IsTemplate(data) {
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(string(data))
+		if err != nil {
+			return nil, err
+		}
+
+		var b bytes.Buffer
+		if err = tmpl.Execute(&b, map[string]string(v)); err != nil {
+			return nil, err
+		}
+		data = b.Bytes()
+	}
+
+	return v.Substitute(data), nil
+}
+
+// Substitute substitutes the ${VAR} variables of data, a variable without value nor default is kept as is.
+func (v Vars) Substitute(data []byte) []byte {
+	return reVariable.ReplaceAllFunc(data, func(match []byte) []byte {
+		m := reVariable.FindSubmatch(match)
+		if value, ok := v[string(m[1])]; ok {
+			return []byte(value)
+		}
+		if m[2] != nil {
+			return m[3]
+		}
+		return match
+	})
+}
+
+// Reader returns a reader of files rendering the content returned by read.
+func (v Vars) Reader(read kube.FileReader) kube.FileReader {
+	return func(path string) ([]byte, bool, error) {
+		data, sensitive, err := read(path)
+		if err != nil {
+			return nil, sensitive, err
+		}
+
+		rendered, err := v.Render(path, data)
+		if err != nil && sensitive {
+			// the error may quote the decrypted content
+			return nil, sensitive, fmt.Errorf("render %s: the decrypted content is not a valid template", path)
+		}
+		if err != nil {
+			return nil, sensitive, fmt.Errorf("render %s: %w", path, err)
+		}
+		return rendered, sensitive, nil
+	}
+}
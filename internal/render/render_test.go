@@ -0,0 +1,42 @@
+package render
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	vars := Vars{"region": "eu-west-1", "env": "dev"}
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{name: "variable", data: "region: ${region}", want: "region: eu-west-1"},
+		{name: "default", data: "zone: ${zone:-a}", want: "zone: a"},
+		{name: "value over default", data: "region: ${region:-us-west-1}", want: "region: eu-west-1"},
+		{name: "unknown variable kept", data: "zone: ${zone}", want: "zone: ${zone}"},
+		{
+			name: "literal braces without marker",
+			data: "content: '{{ InstanceIds }}'\nregion: ${region}",
+			want: "content: '{{ InstanceIds }}'\nregion: eu-west-1",
+		},
+		{
+			name: "template",
+			data: "# bean:template\nname: vpc-{{ .env }}\nregion: ${region}",
+			want: "# bean:template\nname: vpc-dev\nregion: eu-west-1",
+		},
+		{name: "template missing variable", data: "#bean:template\nname: {{ .missing }}", wantErr: true},
+		{name: "template syntax", data: "# bean:template\nname: {{ .env", wantErr: true},
+		{name: "marker not on its own line", data: "name: x # bean:template\nid: {{ .missing }}", want: "name: x # bean:template\nid: {{ .missing }}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := vars.Render(tt.name, []byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("Render = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+// Package results stores the outcomes of the apply, readiness and delete of the examples against a cluster.
+package results
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FrangipaneTeam/bean/config"
+	"github.com/FrangipaneTeam/bean/internal/cache"
+	"github.com/FrangipaneTeam/bean/internal/kube"
+)
+
+// StateDir is the directory of the results stores in the user state dir. The results are not a cache,
+// clearing the cache keeps them.
+const StateDir = "bean/results"
+
+// SimulatorContext is the cluster context of the runs against the simulated cluster of the debug mode.
+// They prove nothing about the provider, they are neither recorded nor read.
+const SimulatorContext = "simulator"
+
+const (
+	// StepApply is the apply of the files of an example.
+	StepApply = "apply"
+	// StepReady is the wait for the resources to be Ready and Synced.
+	StepReady = "ready"
+	// StepDelete is the delete of the files of an example.
+	StepDelete = "delete"
+)
+
+// Status is the state of the runs of an example or a kind.
+type Status string
+
+const (
+	// StatusNoExample is a kind no example applies.
+	StatusNoExample Status = "no-example"
+	// StatusNotRun is an example never run against a cluster.
+	StatusNotRun Status = "not-run"
+	// StatusPassed is an example whose last run became Ready.
+	StatusPassed Status = "passed"
+	// StatusFailed is an example whose last run failed.
+	StatusFailed Status = "failed"
+)
+
+// Outcome is the outcome of a step of the run of an example.
+type Outcome struct {
+	// Example is the path of the example file, relative to the provider path.
+	Example         string    `json:"example" yaml:"example"`
+	Step            string    `json:"step" yaml:"step"`
+	Passed          bool      `json:"passed" yaml:"passed"`
+	Error           string    `json:"error,omitempty" yaml:"error,omitempty"`
+	Time            time.Time `json:"time" yaml:"time"`
+	Context         string    `json:"context" yaml:"context"`
+	ProviderVersion string    `json:"providerVersion" yaml:"providerVersion"`
+}
+
+// Status returns the status of the example set by the outcome, empty if it does not decide it:
+// a failed step fails the run and a passed readiness passes it.
+func (o Outcome) Status() Status {
+	switch {
+	case !o.Passed:
+		return StatusFailed
+	case o.Step == StepReady:
+		return StatusPassed
+	}
+	return ""
+}
+
+// Summary returns the status set by the outcome with its time and cluster context.
+func (o Outcome) Summary() string {
+	return fmt.Sprintf("last run %s @ %s on %s", o.Status(), o.Time.Format("2006-01-02 15:04"), o.Context)
+}
+
+// Store is the results store of a provider. It is safe for concurrent use.
+type Store struct {
+	providerPath    string
+	context         string
+	providerVersion string
+	// file is the store, err is set if its path can't be found.
+	file string
+	err  error
+	mu   sync.Mutex
+}
+
+// Dir returns the directory of the results stores, StateDir in $XDG_STATE_HOME or ~/.local/state.
+func Dir() (string, error) {
+	state := os.Getenv("XDG_STATE_HOME")
+	if state == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		state = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(state, filepath.FromSlash(StateDir)), nil
+}
+
+// File returns the results store of the provider path, a JSON line per outcome appended by the TUI
+// and the headless commands. It is outside of the provider repository, keyed by its path as the cache.
+func File(providerPath string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	key, err := cache.Key(providerPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, key+".jsonl"), nil
+}
+
+// New returns the results store of the provider, recording the cluster context and the provider version
+// of the config.
+func New(c config.Provider) *Store {
+	context := SimulatorContext
+	if !c.Debug {
+		context = kube.CurrentContext(c.Kubeconfig, c.KubeContext)
+	}
+	version := c.ProviderVersion()
+	if version == "" {
+		version = gitDescribe(c.Path)
+	}
+	file, err := File(c.Path)
+	return &Store{providerPath: c.Path, context: context, providerVersion: version, file: file, err: err}
+}
+
+// Outcome returns the outcome of a step of the example file, with the cluster context and the provider version
+// of the store. The error is nil if the step passed.
+func (s *Store) Outcome(file, step string, stepErr error) Outcome {
+	o := Outcome{
+		Example:         Name(s.providerPath, file),
+		Step:            step,
+		Passed:          stepErr == nil,
+		Time:            time.Now(),
+		Context:         s.context,
+		ProviderVersion: s.providerVersion,
+	}
+	if stepErr != nil {
+		o.Error = stepErr.Error()
+	}
+	return o
+}
+
+// Record appends the outcome of a step of the example file to the store. The error is nil if the step passed.
+func (s *Store) Record(file, step string, stepErr error) error {
+	return s.Append(s.Outcome(file, step, stepErr))
+}
+
+// Append appends the outcome to the store, unless it is a run against the simulated cluster.
+func (s *Store) Append(o Outcome) error {
+	if o.Context == SimulatorContext {
+		return nil
+	}
+	data, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+
+	if s.err != nil {
+		return fmt.Errorf("no results store: %w", s.err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err = os.MkdirAll(filepath.Dir(s.file), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Last returns the outcome deciding the status of each example of the provider path, keyed by example path.
+// The store is empty if the file does not exist, the runs against the simulated cluster are skipped.
+func Last(providerPath string) (map[string]Outcome, error) {
+	last := map[string]Outcome{}
+	file, err := File(providerPath)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return last, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var o Outcome
+		if err = json.Unmarshal(scanner.Bytes(), &o); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", file, line, err)
+		}
+		if o.Context != SimulatorContext && o.Status() != "" && !o.Time.Before(last[o.Example].Time) {
+			last[o.Example] = o
+		}
+	}
+	return last, scanner.Err()
+}
+
+// Name returns the name of the example file in the store, its path relative to the provider path.
+func Name(providerPath, file string) string {
+	name, err := filepath.Rel(providerPath, file)
+	if err != nil {
+		return file
+	}
+	return name
+}
+
+// gitDescribe returns the git description of the directory, empty if it is not a git repository.
+func gitDescribe(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "describe", "--tags", "--always", "--dirty").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
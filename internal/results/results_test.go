@@ -0,0 +1,129 @@
+package results
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testStore returns a store of the provider path for the cluster context, in a temporary state dir.
+func testStore(t *testing.T, providerPath, context string) *Store {
+	t.Helper()
+	file, err := File(providerPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Store{providerPath: providerPath, context: context, providerVersion: "v0.42.0", file: file}
+}
+
+func TestLast(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	s := testStore(t, dir, "kind-crossplane")
+	sim := testStore(t, dir, SimulatorContext)
+	vpc, subnet := filepath.Join(dir, "examples", "vpc.yaml"), filepath.Join(dir, "examples", "subnet.yaml")
+
+	steps := []struct {
+		store *Store
+		file  string
+		step  string
+		err   error
+	}{
+		{s, vpc, StepApply, nil},
+		{s, vpc, StepReady, errors.New("VPC/vpc not ready")},
+		{sim, vpc, StepReady, nil},
+		{s, subnet, StepApply, nil},
+		{s, subnet, StepReady, nil},
+		{s, subnet, StepDelete, nil},
+		{sim, subnet, StepApply, errors.New("apply Subnet/subnet: quota exceeded")},
+	}
+	for _, st := range steps {
+		if err := st.store.Record(st.file, st.step, st.err); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	last, err := Last(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]Status{"examples/vpc.yaml": StatusFailed, "examples/subnet.yaml": StatusPassed}
+	if len(last) != len(want) {
+		t.Fatalf("Last = %v, want %v", last, want)
+	}
+	for name, status := range want {
+		o := last[name]
+		if o.Status() != status || o.Context == SimulatorContext {
+			t.Errorf("Last[%s] = %s on %s, want %s", name, o.Status(), o.Context, status)
+		}
+	}
+}
+
+func TestRecord(t *testing.T) {
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+	dir := t.TempDir()
+	s := testStore(t, dir, "kind-crossplane")
+	if err := s.Record(filepath.Join(dir, "vpc.yaml"), StepReady, errors.New("VPC/vpc not ready")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the store is in the state dir, not in the provider repository
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Record() wrote %v in the provider path", entries)
+	}
+	if !strings.HasPrefix(s.file, filepath.Join(state, "bean", "results")) {
+		t.Errorf("File() = %s, want a file of the state dir", s.file)
+	}
+
+	last, err := Last(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := last["vpc.yaml"]
+	want := Outcome{
+		Example: "vpc.yaml", Step: StepReady, Error: "VPC/vpc not ready",
+		Context: "kind-crossplane", ProviderVersion: "v0.42.0", Time: got.Time,
+	}
+	if !reflect.DeepEqual(got, want) || got.Time.IsZero() {
+		t.Errorf("Last() = %+v, want %+v", got, want)
+	}
+
+	// the stores of the providers are apart
+	if other, err := Last(t.TempDir()); err != nil || len(other) != 0 {
+		t.Errorf("Last() of another provider = %v, %v, want none", other, err)
+	}
+}
+
+func TestLastInvalid(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	file, err := File(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(file, []byte("{\"example\":\"vpc.yaml\"}\nnot json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Last(dir); err == nil || !strings.HasPrefix(err.Error(), file+":2: ") {
+		t.Errorf("Last() error = %v, want the line of the invalid outcome", err)
+	}
+}
+
+func TestAppendSimulator(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	sim := testStore(t, dir, SimulatorContext)
+	if err := sim.Record(filepath.Join(dir, "vpc.yaml"), StepReady, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(sim.file); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("a simulated run was recorded: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the results as a JUnit XML report.
+func WriteJUnit(w io.Writer, name string, results []Result) error {
+	suite := junitTestSuite{
+		Name:  name,
+		Tests: len(results),
+	}
+
+	total := 0.0
+	for _, r := range results {
+		tc := junitTestCase{
+			Name:      r.Example.FileName,
+			ClassName: filepath.Base(filepath.Dir(r.Example.FullPath)),
+			Time:      seconds(r.Duration.Seconds()),
+		}
+		total += r.Duration.Seconds()
+
+		if !r.Passed() {
+			suite.Failures++
+			tc.Failure = &junitFailure{
+				Message: r.Err.Error(),
+				Type:    r.Step,
+				Text:    fmt.Sprintf("%s failed for %s\n%s", r.Step, r.Example.FullPath, r.Err),
+			}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = seconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func seconds(s float64) string {
+	return fmt.Sprintf("%.3f", s)
+}
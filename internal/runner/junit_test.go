@@ -0,0 +1,37 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+	"github.com/FrangipaneTeam/bean/internal/kube/sim"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	c := sim.New(sim.Config{Failures: []sim.Failure{{Kind: "Subnet", Message: "invalid cidr"}}})
+	opts := Options{Timeout: 50 * time.Millisecond, Interval: interval}
+	results := []Result{}
+	for i, e := range []*exlist.Example{{FileName: "vpc.yaml", FullPath: vpc}, {FileName: "subnet.yaml", FullPath: subnet}} {
+		res := Run(context.Background(), c, e, opts)
+		// the durations of the runs vary
+		res.Duration = time.Duration(i+1) * 1500 * time.Millisecond
+		results = append(results, res)
+	}
+
+	var got bytes.Buffer
+	if err := WriteJUnit(&got, "bean", results); err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "junit.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != string(want) {
+		t.Errorf("WriteJUnit() =\n%s\nwant\n%s", got.String(), want)
+	}
+}
@@ -0,0 +1,114 @@
+// Package runner runs the examples end-to-end against a cluster.
+package runner
+
+import (
+	"context"
+	"time"
+
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+	"github.com/FrangipaneTeam/bean/internal/kube"
+)
+
+const (
+	// StepApply is the apply step of a run.
+	StepApply = "apply"
+	// StepReady is the wait for readiness step of a run.
+	StepReady = "ready"
+	// StepDelete is the delete step of a run.
+	StepDelete = "delete"
+	// StepDeleted is the wait for deletion step of a run.
+	StepDeleted = "deleted"
+
+	defaultInterval = 5 * time.Second
+)
+
+// Options are the options of a run.
+type Options struct {
+	// Timeout is the time to wait for the resources to be Ready, then to be gone.
+	Timeout time.Duration
+	// Interval is the time between two checks of the resources.
+	Interval time.Duration
+	// WithDependencies applies the dependencies files of the example.
+	WithDependencies bool
+}
+
+// Result is the result of the run of an example.
+type Result struct {
+	Example   *exlist.Example
+	Files     []string
+	Resources []kube.ResourceStatus
+	Duration  time.Duration
+	// Step is the step that failed, empty if the run passed.
+	Step string
+	Err  error
+}
+
+// Passed returns true if the run passed.
+func (r Result) Passed() bool {
+	return r.Err == nil
+}
+
+// Run applies the example, waits for its resources to be Ready, deletes it and waits for its resources to be gone.
+// Dependencies are applied layer by layer and deleted in reverse order.
+// The example is deleted even if it never became Ready or the context is canceled.
+func Run(ctx context.Context, e kube.Executor, example *exlist.Example, opts Options) Result {
+	if opts.Interval == 0 {
+		opts.Interval = defaultInterval
+	}
+
+	start := time.Now()
+	layers := example.FileLayers(opts.WithDependencies)
+	res := Result{
+		Example: example,
+		Files:   example.Files(opts.WithDependencies),
+	}
+
+	if opts.WithDependencies && example.DependenciesErr != nil {
+		res.fail(StepApply, example.DependenciesErr)
+		res.Duration = time.Since(start)
+		return res
+	}
+
+	objs, err := kube.ApplyLayers(ctx, e, layers, opts.Timeout, opts.Interval)
+	if err != nil {
+		res.fail(StepApply, err)
+	}
+
+	if res.Passed() {
+		readyCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+		res.Resources, err = kube.WaitReady(readyCtx, e, objs, opts.Interval)
+		cancel()
+		if err != nil {
+			res.fail(StepReady, err)
+		}
+	}
+
+	// the delete is not canceled with the run, an interrupted run must not leave its resources running,
+	// it waits at most the timeout for each layer to be gone
+	deleteCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Duration(len(layers))*opts.Timeout)
+	defer cancel()
+	deleted, err := kube.DeleteLayers(deleteCtx, e, layers, opts.Timeout, opts.Interval)
+	if err != nil {
+		res.fail(StepDelete, err)
+		res.Duration = time.Since(start)
+		return res
+	}
+
+	deletedCtx, cancelDeleted := context.WithTimeout(deleteCtx, opts.Timeout)
+	defer cancelDeleted()
+	if err = kube.WaitDeleted(deletedCtx, e, deleted, opts.Interval); err != nil {
+		res.fail(StepDeleted, err)
+	}
+
+	res.Duration = time.Since(start)
+	return res
+}
+
+// fail records the first failure of the run.
+func (r *Result) fail(step string, err error) {
+	if r.Err != nil {
+		return
+	}
+	r.Step = step
+	r.Err = err
+}
@@ -0,0 +1,164 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+	"github.com/FrangipaneTeam/bean/internal/kube/sim"
+)
+
+const (
+	interval = 5 * time.Millisecond
+	never    = time.Duration(1 << 62)
+)
+
+var (
+	vpc    = filepath.Join("testdata", "ec2", "vpc.yaml")
+	subnet = filepath.Join("testdata", "ec2", "subnet.yaml")
+)
+
+// recorder records the calls to the simulated cluster.
+type recorder struct {
+	*sim.Cluster
+	calls []string
+}
+
+func (r *recorder) Apply(ctx context.Context, files []string) ([]*unstructured.Unstructured, error) {
+	r.record("apply", files)
+	return r.Cluster.Apply(ctx, files)
+}
+
+func (r *recorder) Delete(ctx context.Context, files []string) ([]*unstructured.Unstructured, error) {
+	r.record("delete", files)
+	return r.Cluster.Delete(ctx, files)
+}
+
+func (r *recorder) record(verb string, files []string) {
+	for _, f := range files {
+		verb += " " + filepath.Base(f)
+	}
+	r.calls = append(r.calls, verb)
+}
+
+// subnetExample returns the example of the subnet, depending on the vpc.
+func subnetExample() *exlist.Example {
+	return &exlist.Example{
+		FileName:           "subnet.yaml",
+		FullPath:           subnet,
+		DependenciesLayers: [][]string{{vpc}},
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// remaining returns the objects left in the cluster.
+func remaining(t *testing.T, c *sim.Cluster) int {
+	t.Helper()
+	objs, err := c.Managed(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(objs)
+}
+
+func TestRun(t *testing.T) {
+	applied := []string{"apply vpc.yaml", "apply subnet.yaml", "delete subnet.yaml", "delete vpc.yaml"}
+	tests := []struct {
+		name    string
+		config  sim.Config
+		depsErr error
+		step    string
+		err     string
+		calls   []string
+	}{
+		{
+			name:  "passed",
+			calls: applied,
+		},
+		{
+			name:   "dependency not ready",
+			config: sim.Config{Failures: []sim.Failure{{Kind: "VPC", Message: "invalid cidr"}}},
+			step:   StepApply,
+			err:    "dependencies layer 1: VPC/vpc (Synced: invalid cidr) not ready: context deadline exceeded",
+			calls:  []string{"apply vpc.yaml", "delete subnet.yaml", "delete vpc.yaml"},
+		},
+		{
+			name:   "apply failed",
+			config: sim.Config{Failures: []sim.Failure{{Kind: "Subnet", Apply: true, Message: "forbidden"}}},
+			step:   StepApply,
+			err:    "apply Subnet/subnet: forbidden",
+			calls:  applied,
+		},
+		{
+			name: "not ready, the first failure is kept",
+			config: sim.Config{Deleted: never, Failures: []sim.Failure{
+				{Kind: "Subnet", Condition: "Ready", Message: "creating"},
+			}},
+			step:  StepReady,
+			err:   "Subnet/subnet (Ready: creating) not ready: context deadline exceeded",
+			calls: []string{"apply vpc.yaml", "apply subnet.yaml", "delete subnet.yaml"},
+		},
+		{
+			name:   "not deleted",
+			config: sim.Config{Deleted: never},
+			step:   StepDelete,
+			err:    "layer 2: Subnet/subnet still exists: context deadline exceeded",
+			calls:  []string{"apply vpc.yaml", "apply subnet.yaml", "delete subnet.yaml"},
+		},
+		{
+			name:    "dependencies not ordered",
+			depsErr: errors.New("dependency cycle"),
+			step:    StepApply,
+			err:     "dependency cycle",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{Cluster: sim.New(tt.config)}
+			e := subnetExample()
+			e.DependenciesErr = tt.depsErr
+
+			opts := Options{Timeout: 50 * time.Millisecond, Interval: interval, WithDependencies: true}
+			res := Run(context.Background(), r, e, opts)
+			if res.Step != tt.step {
+				t.Errorf("Run() step = %q, want %q", res.Step, tt.step)
+			}
+			if err := errString(res.Err); err != tt.err {
+				t.Errorf("Run() error = %q, want %q", err, tt.err)
+			}
+			if !reflect.DeepEqual(r.calls, tt.calls) {
+				t.Errorf("Run() calls = %q, want %q", r.calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestRunCanceledDeletes(t *testing.T) {
+	// the subnet is never ready, the run is canceled during the wait
+	c := sim.New(sim.Config{Failures: []sim.Failure{{Kind: "Subnet", Condition: "Ready", Message: "creating"}}})
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	res := Run(ctx, c, subnetExample(), Options{Timeout: time.Minute, Interval: interval, WithDependencies: true})
+	if res.Step != StepReady || !errors.Is(res.Err, context.Canceled) {
+		t.Errorf("Run() = %s %v, want a canceled wait for readiness", res.Step, res.Err)
+	}
+	if n := remaining(t, c); n != 0 {
+		t.Errorf("Run() left %d objects, want the canceled run deleted", n)
+	}
+}
@@ -0,0 +1,31 @@
+package scaffold
+
+import (
+	"fmt"
+
+	"github.com/FrangipaneTeam/bean/config"
+	"github.com/FrangipaneTeam/bean/internal/crd"
+)
+
+// Kind writes the example of the kind, kind or group/kind, in the first examples directory of the provider
+// and returns its path.
+func Kind(c config.Provider, kind string) (string, error) {
+	crdsRoots, err := c.CRDsRoots()
+	if err != nil {
+		return "", err
+	}
+	crds, err := crd.GetCRDs(crdsRoots...)
+	if err != nil {
+		return "", fmt.Errorf("can't get crds: %w", err)
+	}
+	found, err := Find(crds, kind)
+	if err != nil {
+		return "", err
+	}
+
+	roots, err := c.ExamplesRoots()
+	if err != nil {
+		return "", err
+	}
+	return Write(roots[0], found)
+}
@@ -0,0 +1,222 @@
+// Package scaffold generates the skeleton of an example from the schema of a CRD.
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/FrangipaneTeam/bean/internal/crd"
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+)
+
+// Placeholder is the value of the required string fields.
+const Placeholder = "CHANGEME"
+
+// ErrExists is returned when the example file already exists, it is never overwritten.
+var ErrExists = errors.New("the example already exists")
+
+// selectorSuffixes are the suffixes of the selectors of ids, stripped to guess the selected kind.
+var selectorSuffixes = []string{"IdSelector", "IdsSelector"}
+
+// Find returns the CRD of the kind, kind or group/kind, case insensitive.
+func Find(crds []crd.CRD, kind string) (crd.CRD, error) {
+	group, name, hasGroup := strings.Cut(kind, "/")
+	if !hasGroup {
+		name, group = group, ""
+	}
+
+	found := []crd.CRD{}
+	for _, c := range crds {
+		if strings.EqualFold(c.Spec.Names.Kind, name) && (group == "" || strings.EqualFold(c.Spec.Group, group)) {
+			found = append(found, c)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return crd.CRD{}, fmt.Errorf("no CRD for kind %s", kind)
+	case 1:
+		return found[0], nil
+	}
+
+	candidates := make([]string, 0, len(found))
+	for _, c := range found {
+		candidates = append(candidates, c.Spec.Group+"/"+c.Spec.Names.Kind)
+	}
+	return crd.CRD{}, fmt.Errorf("kind %s is ambiguous, use one of %s", kind, strings.Join(candidates, ", "))
+}
+
+// Path returns the path of the example of the CRD in the examples root, like examples/ec2/subnet.yaml.
+func Path(root string, c crd.CRD) string {
+	dir, _, _ := strings.Cut(c.Spec.Group, ".")
+	return filepath.Join(root, dir, strings.ToLower(c.Spec.Names.Kind)+".yaml")
+}
+
+// Write writes the example of the CRD in the examples root and returns its path.
+// It returns ErrExists if the file exists.
+func Write(root string, c crd.CRD) (string, error) {
+	data, err := Generate(c)
+	if err != nil {
+		return "", err
+	}
+
+	path := Path(root, c)
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return "", fmt.Errorf("%s: %w", path, ErrExists)
+	}
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err = f.Write(data); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Generate returns the example of the storage version of the CRD, with the required forProvider fields
+// set to placeholders of their type and stubs for the selectors of ids.
+func Generate(c crd.CRD) ([]byte, error) {
+	v := c.StorageVersion()
+	if v == nil {
+		return nil, fmt.Errorf("CRD %s has no version", c.Spec.Names.Kind)
+	}
+
+	r := exlist.Resource{
+		APIVersion: c.Spec.Group + "/" + v.Name,
+		Kind:       c.Spec.Names.Kind,
+	}
+	name := strings.ToLower(c.Spec.Names.Kind)
+
+	forProvider := mapping()
+	if s := c.SpecSchema().Property("forProvider"); s != nil {
+		forProvider = requiredFields(s, s.RequiredProperties(c.SpecSchema(), "forProvider"))
+		addSelectors(forProvider, s)
+	}
+
+	doc := mapping(
+		"apiVersion", str(r.APIVersion),
+		"kind", str(r.Kind),
+		"metadata", mapping(
+			"annotations", mapping(exlist.AnnotationExampleID, str(r.ExpectedExampleID())),
+			"labels", mapping(exlist.LabelExampleName, str(name)),
+			"name", str(name),
+		),
+		"spec", mapping(
+			"forProvider", forProvider,
+			"providerConfigRef", mapping("name", str("default")),
+		),
+	)
+
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// requiredFields returns the required fields of the object with placeholders, in the order of required.
+func requiredFields(s *crd.Schema, required []string) *yaml.Node {
+	n := mapping()
+	for _, name := range required {
+		if p := s.Properties[name]; p != nil {
+			n.Content = append(n.Content, str(name), placeholder(p))
+		}
+	}
+	return n
+}
+
+// placeholder returns a value of the type of the schema.
+func placeholder(s *crd.Schema) *yaml.Node {
+	if len(s.Enum) > 0 {
+		n := &yaml.Node{}
+		_ = n.Encode(s.Enum[0])
+		n.LineComment = fmt.Sprintf("one of %v", s.Enum)
+		return n
+	}
+
+	switch s.Type {
+	case "object":
+		if s.IsMap() {
+			return mapping()
+		}
+		return requiredFields(s, s.RequiredProperties(nil, ""))
+	case "array":
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if s.Items != nil {
+			n.Content = append(n.Content, placeholder(s.Items))
+		}
+		return n
+	case "integer":
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: "0", LineComment: s.Type}
+	case "number":
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: "0.0", LineComment: s.Type}
+	case "boolean":
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "false", LineComment: s.Type}
+	}
+	n := str(Placeholder)
+	n.LineComment = "string"
+	return n
+}
+
+// addSelectors adds a selector stub for each selector of an id of the object, like vpcIdSelector,
+// and removes the id it resolves from the required fields.
+func addSelectors(n *yaml.Node, s *crd.Schema) {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, suffix := range selectorSuffixes {
+			target, ok := strings.CutSuffix(name, suffix)
+			if !ok || target == "" {
+				continue
+			}
+			removeKey(n, strings.TrimSuffix(name, "Selector"))
+			n.Content = append(n.Content, str(name), mapping(
+				"matchLabels", mapping(exlist.LabelExampleName, str(strings.ToLower(target))),
+			))
+		}
+	}
+}
+
+func mapping(pairs ...interface{}) *yaml.Node {
+	n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		n.Content = append(n.Content, str(pairs[i].(string)), pairs[i+1].(*yaml.Node))
+	}
+	return n
+}
+
+func str(v string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+}
+
+// removeKey removes the key of the mapping.
+func removeKey(n *yaml.Node, key string) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			n.Content = append(n.Content[:i], n.Content[i+2:]...)
+			return
+		}
+	}
+}
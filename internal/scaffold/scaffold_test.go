@@ -0,0 +1,27 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FrangipaneTeam/bean/internal/crd"
+)
+
+func TestGenerate(t *testing.T) {
+	c, err := crd.ReadFile(filepath.Join("..", "crd", "testdata", "ec2.aws.upbound.io_vpcendpoints.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Generate(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "vpcendpoint.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
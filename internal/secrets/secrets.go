@@ -0,0 +1,175 @@
+// Package secrets decrypts the age and SOPS encrypted sidecar files in memory.
+package secrets
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+
+	"github.com/FrangipaneTeam/bean/config"
+)
+
+// Format is the encryption format of a file.
+type Format string
+
+const (
+	// FormatPlain is a file that is not encrypted.
+	FormatPlain Format = ""
+	// FormatAge is a file encrypted with age, armored or binary.
+	FormatAge Format = "age"
+	// FormatSOPS is a yaml file with values encrypted by SOPS.
+	FormatSOPS Format = "sops"
+)
+
+const (
+	// EnvAgeKey holds age identities, as used by SOPS.
+	EnvAgeKey = "SOPS_AGE_KEY"
+	// EnvAgeKeyFile is the path of an age identities file, as used by SOPS.
+	EnvAgeKeyFile = "SOPS_AGE_KEY_FILE"
+
+	ageBinaryHeader = "age-encryption.org/v1"
+)
+
+// ErrNoKey is returned when an encrypted file is read without age identity.
+var ErrNoKey = fmt.Errorf("no age key found, set %s, %s or secrets.ageKeyFile in the config", EnvAgeKey, EnvAgeKeyFile)
+
+// Detect returns the encryption format of the file content.
+func Detect(data []byte) Format {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte(armor.Header)), bytes.HasPrefix(data, []byte(ageBinaryHeader)):
+		return FormatAge
+	case isSOPS(data):
+		return FormatSOPS
+	}
+	return FormatPlain
+}
+
+// Keyring holds the age identities used to decrypt the files.
+type Keyring struct {
+	identities []age.Identity
+	// err is the error loading the identities, reported only when a file needs them.
+	err error
+}
+
+// LoadKeyring loads the age identities from $SOPS_AGE_KEY, $SOPS_AGE_KEY_FILE,
+// the secrets.ageKeyFile key of the config and the default SOPS key file.
+// A key that can't be loaded is an error of the files that can't be decrypted without it, not of the keyring.
+func LoadKeyring(c config.Provider) *Keyring {
+	k := &Keyring{}
+	var errs []error
+
+	if key := os.Getenv(EnvAgeKey); key != "" {
+		if err := k.add(strings.NewReader(key)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", EnvAgeKey, err))
+		}
+	}
+
+	files := []string{os.Getenv(EnvAgeKeyFile)}
+	if c.Viper != nil {
+		files = append(files, c.Viper.GetString("secrets.ageKeyFile"))
+	}
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		if err := k.addFile(file); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// the default key file of SOPS is optional
+	if dir, err := os.UserConfigDir(); err == nil {
+		err = k.addFile(filepath.Join(dir, "sops", "age", "keys.txt"))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+
+	k.err = errors.Join(errs...)
+	return k
+}
+
+func (k *Keyring) addFile(path string) error {
+	path = expandHome(path)
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err = k.add(bufio.NewReader(f)); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func (k *Keyring) add(r io.Reader) error {
+	identities, err := age.ParseIdentities(r)
+	if err != nil {
+		return err
+	}
+	k.identities = append(k.identities, identities...)
+	return nil
+}
+
+// ReadFile reads the file and decrypts it in memory if it is encrypted.
+// sensitive is true if the content was decrypted, it must not be written or shown.
+func (k *Keyring) ReadFile(path string) (data []byte, sensitive bool, err error) {
+	data, err = os.ReadFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	format := Detect(data)
+	switch format {
+	case FormatAge:
+		data, err = k.decryptAge(data)
+	case FormatSOPS:
+		data, err = k.decryptSOPS(data)
+	default:
+		return data, false, nil
+	}
+
+	if err != nil {
+		return nil, true, fmt.Errorf("decrypt %s file %s: %w", format, path, err)
+	}
+	return data, true, nil
+}
+
+// decryptAge decrypts an age encrypted content, armored or binary.
+func (k *Keyring) decryptAge(data []byte) ([]byte, error) {
+	if len(k.identities) == 0 {
+		return nil, errors.Join(ErrNoKey, k.err)
+	}
+
+	var src io.Reader = bytes.NewReader(data)
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(armor.Header)) {
+		src = armor.NewReader(bytes.NewReader(bytes.TrimSpace(data)))
+	}
+
+	r, err := age.Decrypt(src, k.identities...)
+	if err != nil {
+		return nil, errors.Join(err, k.err)
+	}
+	return io.ReadAll(r)
+}
+
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,201 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/FrangipaneTeam/bean/config"
+)
+
+// The fixtures of testdata are encrypted for the age key of key.txt by the sops and age binaries:
+//
+//	sops --encrypt --age <recipient> --encrypted-regex '^(data|stringData)$' secret.yaml > secret.sops.yaml
+//	sops --encrypt --age <recipient> comments.yaml > comments.sops.yaml
+//	age -r <recipient> -a -o secret.age secret.yaml
+
+// testKeyring returns the keyring of the age key of the fixtures, without the keys of the user.
+func testKeyring(t *testing.T) *Keyring {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(EnvAgeKey, "")
+	t.Setenv(EnvAgeKeyFile, filepath.Join("testdata", "key.txt"))
+
+	k := LoadKeyring(config.Provider{})
+	if k.err != nil {
+		t.Fatalf("load keyring: %v", k.err)
+	}
+	return k
+}
+
+func readYAML(t *testing.T, data []byte) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return v
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		file string
+		want Format
+	}{
+		{"secret.yaml", FormatPlain},
+		{"secret.age", FormatAge},
+		{"secret.sops.yaml", FormatSOPS},
+		{"comments.sops.yaml", FormatSOPS},
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Detect(data); got != tt.want {
+			t.Errorf("Detect(%s) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	k := testKeyring(t)
+	tests := []struct {
+		file      string
+		plain     string
+		sensitive bool
+	}{
+		{"secret.yaml", "secret.yaml", false},
+		{"secret.age", "secret.yaml", true},
+		{"secret.sops.yaml", "secret.yaml", true},
+		{"comments.sops.yaml", "comments.yaml", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, sensitive, err := k.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatalf("ReadFile: %v", err)
+			}
+			if sensitive != tt.sensitive {
+				t.Errorf("sensitive = %v, want %v", sensitive, tt.sensitive)
+			}
+
+			plain, err := os.ReadFile(filepath.Join("testdata", tt.plain))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := readYAML(t, data), readYAML(t, plain); !reflect.DeepEqual(got, want) {
+				t.Errorf("decrypted %s = %v, want %v", tt.file, got, want)
+			}
+			if strings.Contains(string(data), sopsKey+":") {
+				t.Errorf("decrypted %s has the sops metadata", tt.file)
+			}
+		})
+	}
+}
+
+func TestReadFileComments(t *testing.T) {
+	k := testKeyring(t)
+	data, _, err := k.ReadFile(filepath.Join("testdata", "comments.sops.yaml"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	for _, comment := range []string{"# the database password of the example", "# rotated by hand"} {
+		if !strings.Contains(string(data), comment) {
+			t.Errorf("decrypted file has no %q comment:\n%s", comment, data)
+		}
+	}
+}
+
+func TestReadFileTampered(t *testing.T) {
+	k := testKeyring(t)
+	data, err := os.ReadFile(filepath.Join("testdata", "comments.sops.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(data), "\n")
+
+	tests := []struct {
+		name   string
+		tamper func([]string) []string
+		want   string
+	}{
+		{
+			name: "value removed",
+			tamper: func(lines []string) []string {
+				return removeLine(t, lines, "    enabled: ")
+			},
+			want: "mac mismatch",
+		},
+		{
+			name: "values swapped",
+			tamper: func(lines []string) []string {
+				name, namespace := indexOf(t, lines, "    name: "), indexOf(t, lines, "    namespace: ")
+				lines[name], lines[namespace] = "    name: "+strings.SplitN(lines[namespace], ": ", 2)[1],
+					"    namespace: "+strings.SplitN(lines[name], ": ", 2)[1]
+				return lines
+			},
+			// the path of a value is authenticated with it
+			want: "message authentication failed",
+		},
+		{
+			name: "plain value added",
+			tamper: func(lines []string) []string {
+				i := indexOf(t, lines, "stringData:")
+				return append(lines[:i+1], append([]string{"    user: admin"}, lines[i+1:]...)...)
+			},
+			want: "does not match sops' data format",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tampered.sops.yaml")
+			tampered := tt.tamper(append([]string{}, lines...))
+			if err := os.WriteFile(path, []byte(strings.Join(tampered, "\n")), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := k.ReadFile(path); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ReadFile error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadKeyringMissingKeyFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(EnvAgeKey, "")
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	t.Setenv(EnvAgeKeyFile, missing)
+	k := LoadKeyring(config.Provider{})
+
+	if _, _, err := k.ReadFile(filepath.Join("testdata", "secret.yaml")); err != nil {
+		t.Errorf("ReadFile of a plain file: %v", err)
+	}
+	for _, file := range []string{"secret.age", "secret.sops.yaml"} {
+		_, _, err := k.ReadFile(filepath.Join("testdata", file))
+		if err == nil || !strings.Contains(err.Error(), missing) {
+			t.Errorf("ReadFile(%s) error = %v, want the missing key file", file, err)
+		}
+	}
+}
+
+func indexOf(t *testing.T, lines []string, prefix string) int {
+	t.Helper()
+	for i, l := range lines {
+		if strings.HasPrefix(l, prefix) {
+			return i
+		}
+	}
+	t.Fatalf("no line starting with %q", prefix)
+	return -1
+}
+
+func removeLine(t *testing.T, lines []string, prefix string) []string {
+	t.Helper()
+	i := indexOf(t, lines, prefix)
+	return append(lines[:i], lines[i+1:]...)
+}
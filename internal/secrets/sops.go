@@ -0,0 +1,132 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/getsops/sops/v3"
+	"github.com/getsops/sops/v3/aes"
+	sopsage "github.com/getsops/sops/v3/age"
+	sopsconfig "github.com/getsops/sops/v3/config"
+	"github.com/getsops/sops/v3/logging"
+	sopsyaml "github.com/getsops/sops/v3/stores/yaml"
+	"gopkg.in/yaml.v3"
+)
+
+const sopsKey = "sops"
+
+func init() {
+	// the errors are returned, the logs of SOPS would be written over the TUI
+	for _, l := range logging.Loggers {
+		l.SetOutput(io.Discard)
+	}
+}
+
+// isSOPS returns true if the yaml has the metadata of a SOPS encrypted file.
+func isSOPS(data []byte) bool {
+	if !bytes.Contains(data, []byte("ENC[AES256_GCM")) {
+		return false
+	}
+	docs, err := decodeDocuments(data)
+	if err != nil {
+		return false
+	}
+	for _, doc := range docs {
+		if value(doc, sopsKey) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// decryptSOPS decrypts a SOPS encrypted yaml, values and comments, checks its MAC and returns the yaml
+// without the SOPS metadata.
+// The tree decryption of the sops library imports its KMS, PGP and Vault key sources, decrypting only the
+// age key group would not spare their modules.
+func (k *Keyring) decryptSOPS(data []byte) ([]byte, error) {
+	store := sopsyaml.NewStore(&sopsconfig.YAMLStoreConfig{})
+	tree, err := store.LoadEncryptedFile(data)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := k.dataKey(tree.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	cipher := aes.NewCipher()
+	mac, err := tree.Decrypt(key, cipher)
+	if err != nil {
+		return nil, err
+	}
+	originalMac, err := cipher.Decrypt(tree.Metadata.MessageAuthenticationCode, key, tree.Metadata.LastModified.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt mac: %w", err)
+	}
+	if originalMac != mac {
+		return nil, errors.New("mac mismatch, the file has been modified")
+	}
+
+	return store.EmitPlainFile(tree.Branches)
+}
+
+// dataKey decrypts the data key of the file, with the age identities of the keyring first, then with the
+// keys SOPS finds itself: KMS, PGP, Vault or age keys of the environment.
+func (k *Keyring) dataKey(meta sops.Metadata) ([]byte, error) {
+	if len(k.identities) > 0 {
+		for _, group := range meta.KeyGroups {
+			for _, masterKey := range group {
+				ageKey, ok := masterKey.(*sopsage.MasterKey)
+				if !ok {
+					continue
+				}
+				sopsage.ParsedIdentities(k.identities).ApplyToMasterKey(ageKey)
+				if key, err := ageKey.Decrypt(); err == nil {
+					return key, nil
+				}
+			}
+		}
+	}
+
+	key, err := meta.GetDataKey()
+	if err != nil {
+		return nil, errors.Join(err, k.err)
+	}
+	return key, nil
+}
+
+func decodeDocuments(data []byte) ([]*yaml.Node, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	docs := []*yaml.Node{}
+	for {
+		doc := &yaml.Node{}
+		err := dec.Decode(doc)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+}
+
+// value returns the value of the key of a mapping or of the mapping of a document.
+func value(n *yaml.Node, key string) *yaml.Node {
+	if n.Kind == yaml.DocumentNode && len(n.Content) == 1 {
+		n = n.Content[0]
+	}
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
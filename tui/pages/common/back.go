@@ -9,6 +9,11 @@ func (m *Model) Back() (*Model, []tea.Cmd) {
 
 	if len(m.contextToStop) > 0 {
 		m.ClearContextToStop()
+	} else if m.viewName == PRessources && m.ex.Depth() > 1 {
+		// go up one directory and keep the keys of the list
+		var cmd tea.Cmd
+		m.ex, cmd = m.ex.Back()
+		cmds = append(cmds, cmd)
 	} else {
 		m.RestorePreviousKeys()
 		cmdView := m.RestorePreviousView()
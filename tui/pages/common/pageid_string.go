@@ -17,11 +17,16 @@ func _() {
 	_ = x[PK8SGet-6]
 	_ = x[PK8SGetFromRoot-7]
 	_ = x[PError-8]
+	_ = x[PGraph-9]
+	_ = x[PDiagnostics-10]
+	_ = x[PCoverage-11]
+	_ = x[PUntested-12]
+	_ = x[PCRDChanges-13]
 }
 
-const _PageID_name = "PActualPViewPortPRootPRessourcesPPrintActionsPDialogBoxPK8SGetPK8SGetFromRootPError"
+const _PageID_name = "PActualPViewPortPRootPRessourcesPPrintActionsPDialogBoxPK8SGetPK8SGetFromRootPErrorPGraphPDiagnosticsPCoveragePUntestedPCRDChanges"
 
-var _PageID_index = [...]uint8{0, 7, 16, 21, 32, 45, 55, 62, 77, 83}
+var _PageID_index = [...]uint8{0, 7, 16, 21, 32, 45, 55, 62, 77, 83, 89, 101, 110, 119, 130}
 
 func (i PageID) String() string {
 	if i < 0 || i >= PageID(len(_PageID_index)-1) {
@@ -16,6 +16,11 @@ const (
 	PK8SGet
 	PK8SGetFromRoot
 	PError
+	PGraph
+	PDiagnostics
+	PCoverage
+	PUntested
+	PCRDChanges
 )
 
 type PageID int
@@ -23,7 +28,6 @@ type PageID int
 type Page struct {
 	Keys         *keymap.ListKeyMap
 	previousPage PageID
-	oldIndex     int
 }
 
 func BeanPages() map[PageID]*Page {
@@ -35,6 +39,8 @@ func BeanPages() map[PageID]*Page {
 	k8sGetKeys := keymap.NewListKeyMap()
 	dialogBoxKeys := keymap.NewListKeyMap()
 	errorKeys := keymap.NewListKeyMap()
+	coverageKeys := keymap.NewListKeyMap()
+	untestedKeys := keymap.NewListKeyMap()
 
 	rootKeys.EnableRootKeys()
 	kindKeys.EnableKindListKeys()
@@ -43,6 +49,8 @@ func BeanPages() map[PageID]*Page {
 	k8sGetKeys.EnableGetKeys()
 	dialogBoxKeys.EnableDialogBoxKeys()
 	errorKeys.EnableErrorKeys()
+	coverageKeys.EnableCoverageKeys()
+	untestedKeys.EnableUntestedKeys()
 
 	root := &Page{
 		Keys:         rootKeys,
@@ -79,6 +87,31 @@ func BeanPages() map[PageID]*Page {
 		previousPage: PActual,
 	}
 
+	graph := &Page{
+		Keys:         viewportKeys,
+		previousPage: PRessources,
+	}
+
+	diagnostics := &Page{
+		Keys:         viewportKeys,
+		previousPage: PRoot,
+	}
+
+	coverage := &Page{
+		Keys:         coverageKeys,
+		previousPage: PRoot,
+	}
+
+	untested := &Page{
+		Keys:         untestedKeys,
+		previousPage: PCoverage,
+	}
+
+	crdChanges := &Page{
+		Keys:         viewportKeys,
+		previousPage: PRoot,
+	}
+
 	errorP := &Page{
 		Keys:         errorKeys,
 		previousPage: PActual,
@@ -92,6 +125,11 @@ func BeanPages() map[PageID]*Page {
 	pages[PK8SGet] = k8sGet
 	pages[PDialogBox] = dialogBox
 	pages[PError] = errorP
+	pages[PGraph] = graph
+	pages[PDiagnostics] = diagnostics
+	pages[PCoverage] = coverage
+	pages[PUntested] = untested
+	pages[PCRDChanges] = crdChanges
 
 	return pages
 }
@@ -115,10 +153,11 @@ func (m *Model) RestorePreviousView() tea.Cmd {
 	if newPage, ok := m.pages[m.viewName]; ok {
 		switch m.viewName {
 		case PRessources:
-			m.viewName = newPage.previousPage
-			m.ex.CurrentList.Select(m.pages[m.viewName].oldIndex)
-			m.ex, cmd = m.ex.SetRootItems()
-			m.ex.CurrentList.Title = "Choose an example"
+			// go up one directory, the view changes when the root list is reached
+			m.ex, cmd = m.ex.Back()
+			if m.ex.Depth() == 0 {
+				m.viewName = newPage.previousPage
+			}
 
 		// case PDialogBox:
 		// 	if m.keys.Print.Enabled() {
@@ -139,19 +178,8 @@ func (m *Model) RestorePreviousView() tea.Cmd {
 
 // SetView sets the view name.
 func (m *Model) SetViewName(name PageID) {
-	if m.viewName == PRoot {
-		m.pages[m.viewName].oldIndex = m.ex.CurrentList.Index()
-	}
 	m.viewName = name
 	*m.keys = *m.pages[name].Keys
-
-	// if m.ex.CurrentList.FilterState() == list.FilterApplied {
-	if m.viewName == PRessources {
-		m.ex.CurrentList.Select(0)
-		m.ex.CurrentList.ResetFilter()
-		m.ex.CurrentList.Title = "Choose a kind"
-	}
-	// }
 }
 
 // GetView returns the view name.
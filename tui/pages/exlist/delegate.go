@@ -0,0 +1,35 @@
+package exlist
+
+import (
+	"io"
+
+	"github.com/charmbracelet/bubbles/list"
+
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+)
+
+// statusDelegate renders the examples, in the error colour when their last apply failed
+// and in the warning colour when they have lint issues.
+type statusDelegate struct {
+	list.DefaultDelegate
+	failedStyles  list.DefaultItemStyles
+	warningStyles list.DefaultItemStyles
+}
+
+// Render renders an item of the list.
+func (d statusDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	e, ok := item.(*exlist.Example)
+	switch {
+	case ok && e.ReadinessFailed:
+		failed := d.DefaultDelegate
+		failed.Styles = d.failedStyles
+		failed.Render(w, m, index, item)
+		return
+	case ok && len(e.Diagnostics) > 0:
+		warning := d.DefaultDelegate
+		warning.Styles = d.warningStyles
+		warning.Render(w, m, index, item)
+		return
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
+}
@@ -1,15 +1,25 @@
 package exlist
 
 import (
-	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/FrangipaneTeam/bean/internal/exlist"
 )
 
+// parentList is a list left to open one of its directories.
+type parentList struct {
+	name  string
+	index int
+}
+
 // UpdateExamplesList updates the examples list.
-func (m *Model) UpdateExamplesList(examples map[string][]list.Item) {
-	m.exampleList = examples
+func (m *Model) UpdateExamplesList(examples exlist.LoadedExamples) {
+	m.loaded = examples
+	m.exampleList = examples.Examples
 }
 
+// UpdateList shows the list of the directory, the current one if none is given.
+// The root list is shown if the directory no longer exists.
 func (m *Model) UpdateList(params ...string) (*Model, tea.Cmd) {
 	var cmd tea.Cmd
 	title := m.listName
@@ -17,15 +27,82 @@ func (m *Model) UpdateList(params ...string) (*Model, tea.Cmd) {
 		title = params[0]
 	}
 
-	if _, ok := m.exampleList[title]; ok {
-		i := m.exampleList[title]
-		cmd = m.CurrentList.SetItems(i)
-		m.listName = title
+	if _, ok := m.exampleList[title]; !ok {
+		m.parents = nil
+		title = exlist.RootDir
 	}
+
+	i := m.exampleList[title]
+	cmd = m.CurrentList.SetItems(i)
+	m.listName = title
+	m.CurrentList.Title = m.loaded.Title(title)
 	return m, cmd
 }
 
-func (m *Model) SetRootItems() (*Model, tea.Cmd) {
-	cmd := m.CurrentList.SetItems(m.exampleList["-"])
+// Refresh shows the current list again after its examples changed, keeping its filter and the selected item.
+// The items are filtered at once so that the selected item can be found among them.
+func (m *Model) Refresh() *Model {
+	selected := ""
+	if e, ok := m.CurrentList.SelectedItem().(*exlist.Example); ok {
+		selected = e.FullPath
+	}
+	index := m.CurrentList.Index()
+
+	m, cmd := m.UpdateList()
+	if cmd != nil {
+		m.CurrentList, _ = m.CurrentList.Update(cmd())
+	}
+
+	items := m.CurrentList.VisibleItems()
+	for i, item := range items {
+		if e, ok := item.(*exlist.Example); ok && e.FullPath == selected {
+			index = i
+			break
+		}
+	}
+	if index >= len(items) {
+		index = len(items) - 1
+	}
+	m.CurrentList.Select(max(index, 0))
+	return m
+}
+
+// Enter shows the list of the directory, remembering the current list to go back to it.
+func (m *Model) Enter(dir string) (*Model, tea.Cmd) {
+	name := m.loaded.Key(dir)
+	if _, ok := m.exampleList[name]; !ok {
+		return m, nil
+	}
+
+	m.parents = append(m.parents, parentList{name: m.listName, index: m.CurrentList.Index()})
+	m.CurrentList.ResetFilter()
+	m, cmd := m.UpdateList(name)
+	m.CurrentList.Select(0)
+	return m, cmd
+}
+
+// Back shows the parent list of the current one.
+func (m *Model) Back() (*Model, tea.Cmd) {
+	if len(m.parents) == 0 {
+		return m.SetRootItems()
+	}
+
+	parent := m.parents[len(m.parents)-1]
+	m.parents = m.parents[:len(m.parents)-1]
+	m.CurrentList.ResetFilter()
+	m, cmd := m.UpdateList(parent.name)
+	m.CurrentList.Select(parent.index)
 	return m, cmd
 }
+
+// Depth returns the number of lists opened from the root list.
+func (m *Model) Depth() int { return len(m.parents) }
+
+// SetRootItems shows the root list.
+func (m *Model) SetRootItems() (*Model, tea.Cmd) {
+	m.parents = nil
+	return m.UpdateList(exlist.RootDir)
+}
+
+// Examples returns the loaded examples.
+func (m *Model) Examples() exlist.LoadedExamples { return m.loaded }
@@ -11,9 +11,11 @@ import (
 
 type Model struct {
 	listName string
+	parents  []parentList
 	keys     *keymap.ListKeyMap
 
 	// list
+	loaded      exlist.LoadedExamples
 	exampleList map[string][]list.Item
 	CurrentList list.Model
 }
@@ -43,12 +45,26 @@ func New(
 		Underline(true).
 		Bold(true)
 
-	list := list.New(exampleList.Examples["-"],
-		delegate,
+	failedStyles := delegate.Styles
+	failedStyles.NormalDesc = failedStyles.NormalDesc.Foreground(theme.Colour.Error)
+	failedStyles.SelectedDesc = failedStyles.SelectedDesc.Foreground(theme.Colour.Error)
+	failedStyles.DimmedDesc = failedStyles.DimmedDesc.Foreground(theme.Colour.Error)
+
+	warningStyles := delegate.Styles
+	warningStyles.NormalDesc = warningStyles.NormalDesc.Foreground(theme.Colour.Warning)
+	warningStyles.SelectedDesc = warningStyles.SelectedDesc.Foreground(theme.Colour.Warning)
+	warningStyles.DimmedDesc = warningStyles.DimmedDesc.Foreground(theme.Colour.Warning)
+
+	list := list.New(exampleList.Examples[exlist.RootDir],
+		statusDelegate{
+			DefaultDelegate: delegate,
+			failedStyles:    failedStyles,
+			warningStyles:   warningStyles,
+		},
 		width,
 		height,
 	)
-	list.Title = "Choose an example"
+	list.Title = exampleList.Title(exlist.RootDir)
 	list.DisableQuitKeybindings()
 	list.SetShowHelp(false)
 	list.SetStatusBarItemName("example", "examples")
@@ -56,7 +72,9 @@ func New(
 	// list.SetSize()
 
 	return &Model{
+		listName:    exlist.RootDir,
 		keys:        keymap,
+		loaded:      exampleList,
 		exampleList: exampleList.Examples,
 		CurrentList: list,
 	}
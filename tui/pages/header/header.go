@@ -3,6 +3,7 @@ package header
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -19,6 +20,8 @@ import (
 const (
 	headerBlocks           = 2
 	descriptionPaddingLeft = 2
+	// quietSeconds is the time without file activity before the examples are reloaded.
+	quietSeconds = 2
 )
 
 // Model is the model of the header.
@@ -30,7 +33,6 @@ type Model struct {
 	examplesRecentActivity bool
 	spinner                spinner.Model
 	hideNotify             int
-	activityFrom           interface{}
 	notifyCrds             chan examples.NotifyActivity
 	notifyExamples         chan examples.NotifyActivity
 	width                  int
@@ -38,6 +40,8 @@ type Model struct {
 	NotificationOK         string
 	config                 config.Provider
 	theme                  theme.Theme
+	// changedFiles are the example files changed since the last reload.
+	changedFiles map[string]bool
 }
 
 // Init initializes the model.
@@ -82,38 +86,48 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tickMsg:
 		m.hideNotify--
-		if m.hideNotify <= 0 {
-			switch m.activityFrom.(type) {
-			case examples.ResponseCRDMsg:
-				m.crdRecentActivity = false
-				// cmds = append(cmds, tools.Kubectl(m.config.Path+"/package/crds", "apply"))
-			case examples.ResponseExamplesMsg:
-				m.examplesRecentActivity = false
-				cmds = append(cmds, examples.LoadExamples(m.config))
-			}
-		} else {
+		if m.hideNotify > 0 {
 			return m, tick
 		}
+		switch {
+		case m.crdRecentActivity:
+			// the examples are checked against the new CRDs and compared with the old ones
+			cmds = append(cmds, examples.LoadExamples(m.config))
+		case m.examplesRecentActivity:
+			files := make([]string, 0, len(m.changedFiles))
+			for f := range m.changedFiles {
+				files = append(files, f)
+			}
+			sort.Strings(files)
+			cmds = append(cmds, func() tea.Msg { return examples.ChangedFilesMsg{Files: files} })
+		}
+		m.crdRecentActivity, m.examplesRecentActivity = false, false
+		m.changedFiles = nil
 
 	case spinner.TickMsg:
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
 
+	// each activity restarts the countdown, the reload waits for the files to be quiet
 	case examples.ResponseCRDMsg:
-		cmds = append(cmds, examples.WaitForCrdActivity(m.notifyCrds), tick)
-		if !m.crdRecentActivity {
-			m.hideNotify = 5
-			m.crdRecentActivity = true
-			m.activityFrom = msg
+		cmds = append(cmds, examples.WaitForCrdActivity(m.notifyCrds))
+		if !m.crdRecentActivity && !m.examplesRecentActivity {
+			cmds = append(cmds, tick)
 		}
+		m.hideNotify = quietSeconds
+		m.crdRecentActivity = true
 
 	case examples.ResponseExamplesMsg:
-		cmds = append(cmds, examples.WaitForExamplesActivity(m.notifyExamples), tick)
-		if !m.examplesRecentActivity {
-			m.hideNotify = 5
-			m.examplesRecentActivity = true
-			m.activityFrom = msg
+		cmds = append(cmds, examples.WaitForExamplesActivity(m.notifyExamples))
+		if !m.crdRecentActivity && !m.examplesRecentActivity {
+			cmds = append(cmds, tick)
 		}
+		m.hideNotify = quietSeconds
+		m.examplesRecentActivity = true
+		if m.changedFiles == nil {
+			m.changedFiles = map[string]bool{}
+		}
+		m.changedFiles[msg.FileName] = true
 	}
 	cmds = append(cmds, cmd)
 
@@ -177,6 +191,14 @@ func (m Model) View() string {
 	}
 
 	fmt.Fprintf(&dependenciesStatus, "")
+	if m.config.Profile != "" {
+		fmt.Fprintf(
+			&dependenciesStatus,
+			"%s profile %s ",
+			m.theme.Divider,
+			lipgloss.NewStyle().Foreground(m.theme.Colour.Notification).Render(m.config.Profile),
+		)
+	}
 	if common.ShowDependencies {
 		fmt.Fprintf(
 			&dependenciesStatus,
@@ -233,3 +255,8 @@ func (m Model) Width() int {
 func (m *Model) SetWidth(w int) {
 	m.width = w
 }
+
+// SetProfile sets the profile shown in the header and used to reload the examples.
+func (m *Model) SetProfile(profile string) {
+	m.config.Profile = profile
+}
@@ -16,11 +16,16 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/FrangipaneTeam/bean/internal/crd"
+	"github.com/FrangipaneTeam/bean/internal/examples"
 	"github.com/FrangipaneTeam/bean/internal/exlist"
+	"github.com/FrangipaneTeam/bean/internal/kube"
+	"github.com/FrangipaneTeam/bean/internal/results"
 	"github.com/FrangipaneTeam/bean/tui/pages/common"
 	"github.com/FrangipaneTeam/bean/tui/pages/dialogbox"
 	"github.com/FrangipaneTeam/bean/tui/pages/errorpanel"
 	"github.com/FrangipaneTeam/bean/tui/pages/k8s"
+	"github.com/FrangipaneTeam/bean/tui/pages/untested"
 )
 
 const (
@@ -64,6 +69,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			break
 		}
 
+		if m.selectedDirectory() != nil && m.common.GetViewName() == common.PRessources &&
+			(key.Matches(msg, m.keys.Apply) || key.Matches(msg, m.keys.Delete) ||
+				key.Matches(msg, m.keys.Print) || key.Matches(msg, m.keys.ShowGraph)) {
+			cmd = m.pages.CurrentList.NewStatusMessage("Select an example, not a directory")
+			return m, cmd
+		}
+
 		switch {
 		case key.Matches(msg, m.keys.Select):
 			switch view := m.common.GetViewName(); view {
@@ -92,9 +104,6 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 					ctx, cancel := context.WithCancel(context.Background())
 					k8sCmd.Cancel = cancel
-					if m.config.Debug {
-						k8sCmd.Debug = true
-					}
 					m.common.AddContextToStop(cancel)
 					m.k8s.CmdList[k8sCmd.ID] = k8sCmd
 					cmd = k8s.Kubectl(ctx, k8sCmd)
@@ -111,13 +120,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				return m, tea.Batch(cmds...)
 
-			case common.PRoot:
-				title := m.pages.CurrentList.SelectedItem().(*exlist.Example).Title()
+			case common.PRoot, common.PRessources:
+				dir := m.selectedDirectory()
+				if dir == nil {
+					return m, nil
+				}
 
-				m.pages, cmd = m.pages.UpdateList(title)
+				m.pages, cmd = m.pages.Enter(dir.FullPath)
 				m.common.SetViewName(common.PRessources)
 
 				return m, cmd
+
+			case common.PUntested:
+				selected, ok := m.untested.Selected()
+				if !ok {
+					return m, nil
+				}
+				return m, untested.Scaffold(m.config, selected)
 			}
 
 		case key.Matches(msg, m.keys.Get), key.Matches(msg, m.keys.Apply):
@@ -155,15 +174,98 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			ctx, cancel := context.WithCancel(context.Background())
 			k8sCmd.Cancel = cancel
-			if m.config.Debug {
-				k8sCmd.Debug = true
-			}
 			m.common.AddContextToStop(cancel)
 			m.k8s.CmdList[k8sCmd.ID] = k8sCmd
 			common.RunningCommands++
 			// m.header.RunningCommands++
 			cmd = k8s.Kubectl(ctx, k8sCmd)
 			return m, cmd
+
+		case key.Matches(msg, m.keys.SwitchProfile):
+			profiles := m.config.Profiles()
+			if len(profiles) == 0 {
+				cmd = m.pages.CurrentList.NewStatusMessage("No profile in the config")
+				return m, cmd
+			}
+
+			profile := m.config.NextProfile()
+			if err := m.k8s.SetProfile(profile); err != nil {
+				return m, func() tea.Msg {
+					return errorpanel.ErrorMsg{
+						Reason:   "could not switch the profile",
+						Cause:    err,
+						FromPage: m.common.GetViewName(),
+					}
+				}
+			}
+			m.config.Profile = profile
+			m.header.SetProfile(profile)
+
+			if profile == "" {
+				profile = "none"
+			}
+			m.header.Notification = fmt.Sprintf("profile %s @ %s", profile, time.Now().Format("15:04:05"))
+			m.header.NotificationOK = m.theme.RunningMark
+			return m, m.reloadExamples()
+
+		case key.Matches(msg, m.keys.Scaffold):
+			if m.common.GetViewName() != common.PCoverage {
+				return m, nil
+			}
+			cmd = m.untested.SetReport(m.markdown.Report())
+			m.common.SetViewName(common.PUntested)
+			return m, cmd
+
+		case key.Matches(msg, m.keys.ShowCRDChanges):
+			view := m.common.GetViewName()
+			if view != common.PRoot && view != common.PRessources {
+				return m, nil
+			}
+			if m.crdChanges == "" {
+				cmd = m.pages.CurrentList.NewStatusMessage("No CRD change since bean started")
+				return m, cmd
+			}
+
+			m.markdown.Viewport.SetContent(m.crdChanges)
+			m.markdown.Viewport.GotoTop()
+			m.common.SetPreviousViewName(common.PCRDChanges, view)
+			m.common.SetViewName(common.PCRDChanges)
+			return m, nil
+
+		case key.Matches(msg, m.keys.ShowGraph):
+			if m.common.GetViewName() != common.PRessources || m.pages.CurrentList.SelectedItem() == nil {
+				return m, nil
+			}
+			selected := m.pages.CurrentList.SelectedItem().(*exlist.Example)
+			tree := m.graph.Tree(selected.FullPath, exlist.RelativeName(m.examplesBase))
+			switch {
+			case selected.DependenciesErr != nil:
+				tree = fmt.Sprintf("%s\n⚠ %s", tree, selected.DependenciesErr)
+			case selected.DependenciesWarning != "":
+				tree = fmt.Sprintf("%s\n⚠ %s", tree, selected.DependenciesWarning)
+			}
+			m.markdown.Viewport.SetContent(tree)
+			m.markdown.Viewport.GotoTop()
+			m.common.SetViewName(common.PGraph)
+			return m, nil
+
+		case key.Matches(msg, m.keys.ShowDiagnostics):
+			view := m.common.GetViewName()
+			if view != common.PRoot && view != common.PRessources {
+				return m, nil
+			}
+
+			// the issues of the selected example, or of all the examples from the root list or a directory
+			checked := m.pages.Examples().List()
+			if selected, ok := m.pages.CurrentList.SelectedItem().(*exlist.Example); ok && !selected.IsDir() {
+				checked = []*exlist.Example{selected}
+			}
+
+			m.markdown.Viewport.SetContent(diagnosticsReport(checked, exlist.RelativeName(m.examplesBase)))
+			m.markdown.Viewport.GotoTop()
+			m.common.SetPreviousViewName(common.PDiagnostics, view)
+			m.common.SetViewName(common.PDiagnostics)
+			return m, nil
 		}
 
 	case k8s.Message:
@@ -181,20 +283,61 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case exlist.LoadedExamples:
 		m.header.Notification = fmt.Sprintf("loaded new examples @ %s", time.Now().Format("15:04:05"))
 		m.header.NotificationOK = m.theme.CheckMark
-		m.pages.UpdateExamplesList(msg.Examples)
+		m.pages.UpdateExamplesList(msg)
+		m.graph = msg.Graph
+		m.examplesBase = msg.Base
 		m.pages, cmd = m.pages.UpdateList()
+
+		// the CRDs are compared only if both sets could be read
+		if msg.CRDs != nil && m.crds != nil {
+			if d := crd.Compare(m.crds, msg.CRDs); !d.Empty() {
+				impacts := exlist.Affected(m.pages.Examples().List(), d)
+				m.crdChanges = crdChangesReport(d, impacts, exlist.RelativeName(m.examplesBase))
+				m.header.Notification = fmt.Sprintf("CRDs changed, %d affected (C) @ %s", len(impacts), time.Now().Format("15:04:05"))
+				m.header.NotificationOK = m.theme.ErrorMark
+				if len(impacts) == 0 {
+					m.header.NotificationOK = m.theme.CheckMark
+				}
+			}
+		}
+		if msg.CRDs != nil {
+			m.crds = msg.CRDs
+		}
 		return m, cmd
 
+	case examples.ChangedFilesMsg:
+		return m, m.readChangedFiles(msg.Files)
+
+	case examples.ReadFilesMsg:
+		// the examples are loaded again when a directory appears or becomes empty
+		loaded := m.pages.Examples()
+		if msg.NewDirectory || !examples.Patch(m.config, loaded, msg.Examples) {
+			return m, m.reloadExamples()
+		}
+		m.graph = loaded.Graph
+		m.pages = m.pages.Refresh()
+		m.header.Notification = fmt.Sprintf("updated %d example(s) @ %s", len(msg.Examples), time.Now().Format("15:04:05"))
+		m.header.NotificationOK = m.theme.CheckMark
+		return m, nil
+
 	case errorpanel.ErrorMsg:
+		if k8sCmd, ok := m.k8s.CmdList[msg.CmdID]; ok && (k8sCmd.Verb == k8sApply || k8sCmd.Verb == k8sDelete) {
+			cmds = append(cmds, m.record(k8sCmd.Example, k8sCmd.Verb, msg.Cause))
+		}
 		m.header.NotificationOK = m.theme.ErrorMark
-		cmd = m.errorPanel.Init()
+		cmds = append(cmds, m.errorPanel.Init())
 		m.errorPanel = m.errorPanel.RaiseError(msg.Reason, msg.Cause)
 		m.common.SetPreviousViewName(common.PError, msg.FromPage.(common.PageID))
 		m.common.SetViewName(common.PError)
 		if m.config.Debug {
 			m.header.Notification = fmt.Sprintf("from %s", msg.FromPage.(common.PageID))
 		}
-		return m, cmd
+		return m, tea.Batch(cmds...)
+
+	case untested.ScaffoldedMsg:
+		m.untested.Remove(msg.Item)
+		cmd = m.untested.List.NewStatusMessage("created " + exlist.RelativeName(m.examplesBase)(msg.Path))
+		return m, tea.Batch(cmd, m.reloadExamples())
 
 	case exlist.ListTestedDone:
 		cmd = m.pages.CurrentList.NewStatusMessage("List tested generated")
@@ -216,8 +359,40 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				cmd = m.tickCmd()
 			}
 		}
+
+		if msg.Verb == k8sDelete {
+			msg.Example.Readiness = ""
+			msg.Example.ReadinessFailed = false
+			cmd = m.record(msg.Example, results.StepDelete, nil)
+		}
+
+		if msg.Verb == k8sApply {
+			cmd = m.record(msg.Example, results.StepApply, nil)
+			if wait := k8s.WaitReady(msg); wait != nil {
+				m.setReadiness(msg, false)
+				cmd = tea.Batch(cmd, wait)
+			}
+		}
 		return m, cmd
 
+	case k8s.ReadyMsg:
+		if msg.Err != nil {
+			m.header.Notification = fmt.Sprintf("wait ready @ %s: %s", time.Now().Format("15:04:05"), msg.Err)
+			m.header.NotificationOK = m.theme.ErrorMark
+			return m, msg.Cmd.PollReady()
+		}
+
+		msg.Cmd.Resources = msg.Resources
+		if m.setReadiness(msg.Cmd, msg.TimedOut) {
+			msg.Cmd.StopWait()
+			var readyErr error
+			if msg.Cmd.Example.ReadinessFailed {
+				readyErr = errors.New(msg.Cmd.Example.Readiness)
+			}
+			return m, m.record(msg.Cmd.Example, results.StepReady, readyErr)
+		}
+		return m, msg.Cmd.PollReady()
+
 	case tickK8SGet:
 		if m.common.GetViewName() == common.PDialogBox {
 			return m, nil
@@ -262,6 +437,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		m.pages.CurrentList.SetSize(m.width, centerH)
 		m.dialogbox.SetSize(m.width, centerH)
+		m.untested.SetSize(m.width, centerH)
 
 		common.Height = m.height
 		common.Width = m.width
@@ -281,6 +457,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmdList)
 	}
 
+	if m.common.GetViewName() == common.PUntested {
+		m.untested, cmd = m.untested.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
 	if m.pages.CurrentList.FilterState() != list.Filtering {
 		m.footer, cmd = m.footer.Update(msg)
 		cmds = append(cmds, cmd)
@@ -376,6 +557,12 @@ func (m model) View() string {
 		case common.PRoot, common.PRessources:
 			center.WriteString(lipgloss.NewStyle().Render(m.pages.CurrentList.View()))
 
+		case common.PUntested:
+			center.WriteString(m.untested.View())
+
+		case common.PGraph, common.PDiagnostics, common.PCoverage, common.PCRDChanges:
+			center.WriteString(m.markdown.Viewport.View())
+
 		case common.PPrintActions:
 			ui := m.k8s.View()
 			m.markdown.Viewport.SetContent(ui)
@@ -403,6 +590,57 @@ func (m model) tickCmd() tea.Cmd {
 	})
 }
 
+// setReadiness shows the readiness progress of the command on its example and in the header.
+// It returns true when the wait is over.
+func (m model) setReadiness(k8sCmd *k8s.Cmd, timedOut bool) bool {
+	progress, failed := k8sCmd.Progress(timedOut)
+	k8sCmd.Example.Readiness = progress
+	k8sCmd.Example.ReadinessFailed = failed
+
+	switch {
+	case failed:
+		m.header.Notification = lipgloss.NewStyle().Foreground(m.theme.Colour.Error).Render(progress)
+		m.header.NotificationOK = m.theme.ErrorMark
+		return true
+	case kube.CountDone(k8sCmd.Resources) == len(k8sCmd.Resources):
+		m.header.Notification = fmt.Sprintf("%s @ %s", progress, time.Now().Format("15:04:05"))
+		m.header.NotificationOK = m.theme.CheckMark
+		return true
+	}
+
+	m.header.Notification = progress
+	m.header.NotificationOK = m.theme.RunningMark
+	return false
+}
+
+// record shows the outcome of a step of the example on it and records it in the results store.
+// The error is nil if the step passed.
+func (m model) record(e *exlist.Example, step string, stepErr error) tea.Cmd {
+	o := m.results.Outcome(e.FullPath, step, stepErr)
+	if o.Status() != "" {
+		e.LastRun = o.Summary()
+	}
+	from := m.common.GetViewName()
+	return func() tea.Msg {
+		if err := m.results.Append(o); err != nil {
+			return errorpanel.ErrorMsg{
+				Reason:   "could not record the result",
+				Cause:    err,
+				FromPage: from,
+			}
+		}
+		return nil
+	}
+}
+
+// selectedDirectory returns the selected item if it is a directory of examples.
+func (m model) selectedDirectory() *exlist.Example {
+	if selected, ok := m.pages.CurrentList.SelectedItem().(*exlist.Example); ok && selected.IsDir() {
+		return selected
+	}
+	return nil
+}
+
 func (m model) generateK8SFiles() (model, *k8s.Cmd, tea.Cmd) {
 	if m.pages.CurrentList.SelectedItem() == nil {
 		cmd := m.errorPanel.Init()
@@ -416,32 +654,121 @@ func (m model) generateK8SFiles() (model, *k8s.Cmd, tea.Cmd) {
 
 	selectedItem := m.pages.CurrentList.SelectedItem().(*exlist.Example)
 
-	file := selectedItem.FileWithPath()
-	extra := selectedItem.HaveExtraFile()
-	secret := selectedItem.HaveSecretFile()
-	files := []string{file}
+	// the verb is set by the caller
+	cmd, err := k8s.NewCmd(selectedItem, "", m.k8s.ShowDependenciesFiles, m.config.WaitTimeout)
+	if err != nil {
+		errCmd := m.errorPanel.Init()
+		m.errorPanel = m.errorPanel.RaiseError("can't order the dependencies", selectedItem.DependenciesErr)
+		m.header.NotificationOK = m.theme.ErrorMark
+		return m, nil, errCmd
+	}
+	cmd.ID = randSeq(5)
 
-	if extra {
-		f := fmt.Sprintf("%s.extra", file)
-		files = append(files, f)
+	executor, err := m.k8s.Executor()
+	if err != nil {
+		errCmd := m.errorPanel.Init()
+		m.errorPanel = m.errorPanel.RaiseError("could not create kubernetes client", err)
+		m.header.NotificationOK = m.theme.ErrorMark
+		return m, nil, errCmd
 	}
+	cmd.Executor = executor
+
+	return m, cmd, nil
+}
 
-	if secret {
-		f := fmt.Sprintf("%s.secret", file)
-		files = append(files, f)
+// reloadExamples reloads the examples with the config of the model and raises the loading errors
+// on the current page.
+func (m model) reloadExamples() tea.Cmd {
+	from := m.common.GetViewName()
+	return func() tea.Msg {
+		switch msg := examples.GenerateExamplesList(m.config).(type) {
+		case *errorpanel.ErrorMsg:
+			msg.FromPage = from
+			return *msg
+		case errorpanel.ErrorMsg:
+			msg.FromPage = from
+			return msg
+		default:
+			return msg
+		}
 	}
+}
 
-	if m.k8s.ShowDependenciesFiles && selectedItem.HaveDependenciesFiles() {
-		d := selectedItem.DependenciesFilesList()
-		files = append(files, d...)
+// readChangedFiles reads the changed example files again and raises the reading errors on the current page.
+func (m model) readChangedFiles(files []string) tea.Cmd {
+	from := m.common.GetViewName()
+	read := examples.ReadFiles(m.config, m.crds, files)
+	return func() tea.Msg {
+		msg := read()
+		if errMsg, ok := msg.(errorpanel.ErrorMsg); ok {
+			errMsg.FromPage = from
+			return errMsg
+		}
+		return msg
 	}
+}
 
-	cmd := &k8s.Cmd{
-		ID:    randSeq(5),
-		Done:  false,
-		Files: files,
-		Kind:  selectedItem.Description(),
+// diagnosticsReport returns the lint issues of the examples, grouped by file.
+func diagnosticsReport(checked []*exlist.Example, name func(string) string) string {
+	var b strings.Builder
+	count := 0
+	for _, e := range checked {
+		if len(e.Diagnostics) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "%s\n", name(e.FullPath))
+		for _, d := range e.Diagnostics {
+			fmt.Fprintf(&b, "  ⚠ line %d: %s (%s)\n", d.Line, d.Message, d.Rule)
+		}
+		b.WriteString("\n")
+		count += len(e.Diagnostics)
 	}
+	return fmt.Sprintf("%d lint issue(s) in %d example(s)\n\n%s", count, len(checked), b.String())
+}
 
-	return m, cmd, nil
+// crdChangesReport returns the changes of the CRDs and the example resources they affect.
+func crdChangesReport(d crd.Diff, impacts []exlist.Impact, name func(string) string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CRDs changed @ %s\n\n", time.Now().Format("15:04:05"))
+
+	for _, k := range d.AddedKinds {
+		fmt.Fprintf(&b, "+ kind %s\n", k)
+	}
+	for _, k := range d.RemovedKinds {
+		fmt.Fprintf(&b, "- kind %s\n", k)
+	}
+	if len(d.AddedKinds)+len(d.RemovedKinds) > 0 {
+		b.WriteString("\n")
+	}
+
+	for _, k := range d.Kinds {
+		fmt.Fprintf(&b, "%s/%s\n", k.Group, k.Kind)
+		for _, v := range k.AddedVersions {
+			fmt.Fprintf(&b, "  + version %s\n", v)
+		}
+		for _, v := range k.RemovedVersions {
+			fmt.Fprintf(&b, "  - version %s\n", v)
+		}
+		if k.OldStorage != "" {
+			fmt.Fprintf(&b, "  ~ storage version %s → %s\n", k.OldStorage, k.NewStorage)
+		}
+		for _, v := range k.Versions {
+			for _, f := range v.AddedFields {
+				fmt.Fprintf(&b, "  + %s spec.%s\n", v.Version, f)
+			}
+			for _, f := range v.RemovedFields {
+				fmt.Fprintf(&b, "  - %s spec.%s\n", v.Version, f)
+			}
+			for _, r := range v.Renamed {
+				fmt.Fprintf(&b, "  ~ %s spec.%s → spec.%s\n", v.Version, r.From, r.To)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "%d affected example resource(s)\n", len(impacts))
+	for _, i := range impacts {
+		fmt.Fprintf(&b, "  ⚠ %s:%d %s: %s\n", name(i.File), i.Line, i.Kind, i.Reason)
+	}
+	return b.String()
 }
@@ -7,8 +7,10 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/FrangipaneTeam/bean/config"
+	"github.com/FrangipaneTeam/bean/internal/crd"
 	ex "github.com/FrangipaneTeam/bean/internal/exlist"
 	"github.com/FrangipaneTeam/bean/internal/keymap"
+	"github.com/FrangipaneTeam/bean/internal/results"
 	"github.com/FrangipaneTeam/bean/internal/theme"
 	"github.com/FrangipaneTeam/bean/tui/pages/common"
 	"github.com/FrangipaneTeam/bean/tui/pages/dialogbox"
@@ -18,6 +20,7 @@ import (
 	"github.com/FrangipaneTeam/bean/tui/pages/header"
 	"github.com/FrangipaneTeam/bean/tui/pages/k8s"
 	"github.com/FrangipaneTeam/bean/tui/pages/md"
+	"github.com/FrangipaneTeam/bean/tui/pages/untested"
 )
 
 type model struct {
@@ -30,6 +33,7 @@ type model struct {
 	errorPanel *errorpanel.Model
 	markdown   *md.Model
 	k8s        *k8s.Model
+	untested   *untested.Model
 
 	config config.Provider
 
@@ -40,6 +44,15 @@ type model struct {
 
 	pages     *exlist.Model
 	pagesList map[common.PageID]*common.Page
+	graph     *ex.Graph
+	// examplesBase is the directory the example names are relative to.
+	examplesBase string
+	// crds are the CRDs of the last loaded examples, compared with the new ones when they change.
+	crds []crd.CRD
+	// crdChanges is the report of the last change of the CRDs, empty if they did not change.
+	crdChanges string
+	// results records the outcomes of the apply, readiness and delete of the examples.
+	results *results.Store
 
 	width        int
 	height       int
@@ -98,7 +111,7 @@ func New(e ex.LoadedExamples, width, height int, c config.Provider) model {
 		pagesModel,
 	)
 	footer.SetCommonModel(commonM)
-	k8s := k8s.New(rootKeys, commonM, pagesModel)
+	k8s := k8s.New(rootKeys, commonM, pagesModel, c)
 	markdown := md.New(
 		width-h,
 		height-v-headerHeight-footerHeight,
@@ -114,12 +127,17 @@ func New(e ex.LoadedExamples, width, height int, c config.Provider) model {
 		common:     commonM,
 		errorPanel: errorPanel,
 
-		markdown:  markdown,
-		dialogbox: dialogbox,
-		k8s:       k8s,
-		config:    c,
-		pages:     pagesModel,
-		pagesList: common.BeanPages(),
-		theme:     theme,
+		markdown:     markdown,
+		dialogbox:    dialogbox,
+		k8s:          k8s,
+		untested:     untested.New(width-h, height-v-headerHeight-footerHeight),
+		config:       c,
+		pages:        pagesModel,
+		pagesList:    common.BeanPages(),
+		graph:        e.Graph,
+		examplesBase: e.Base,
+		crds:         e.CRDs,
+		results:      results.New(c),
+		theme:        theme,
 	}
 }
@@ -4,11 +4,17 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/FrangipaneTeam/bean/config"
 	"github.com/FrangipaneTeam/bean/internal/exlist"
+	"github.com/FrangipaneTeam/bean/internal/kube"
+	"github.com/FrangipaneTeam/bean/internal/kube/sim"
+	"github.com/FrangipaneTeam/bean/internal/render"
+	"github.com/FrangipaneTeam/bean/internal/secrets"
 	"github.com/FrangipaneTeam/bean/tui/pages/common"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 // Init initializes the model.
@@ -69,8 +75,10 @@ func (m Model) View() string {
 	selectedFile := selected.FileWithPath()
 	yamlFile := ""
 
-	if selected.HaveSecretFile() {
-		yamlFile = fmt.Sprintf("%s,%s.secret", selectedFile, selectedFile)
+	// an encrypted secret file is decrypted in memory by bean, only the commands to do it are shown
+	encrypted := selected.SecretEncryption != ""
+	if selected.HaveSecretFile() && !encrypted {
+		yamlFile = fmt.Sprintf("%s,%s", selectedFile, selected.SecretFile)
 	} else {
 		yamlFile = selectedFile
 	}
@@ -82,8 +90,22 @@ func (m Model) View() string {
 		fmt.Sprintf("* kubectl get -f %s", yamlFile),
 	}
 
+	if selected.HaveSecretFile() && encrypted {
+		decrypt := decryptCommand(selected)
+		str = append(str,
+			fmt.Sprintf("# Secret file (%s encrypted, decrypted in memory by bean) :", selected.SecretEncryption),
+			fmt.Sprintf("* %s | kubectl apply -f -", decrypt),
+			fmt.Sprintf("* %s | kubectl delete -f -", decrypt),
+		)
+	}
+
+	if m.config.Profile != "" {
+		// kubectl applies the raw files, the variables of the profile are rendered by bean only
+		str = append(str, fmt.Sprintf("# Profile %s (variables rendered in memory by bean, not by kubectl)", m.config.Profile))
+	}
+
 	if selected.HaveExtraFile() {
-		extraFile := selectedFile + ".extra"
+		extraFile := selected.ExtraFile
 		str = append(str,
 			"# Extra file:",
 			fmt.Sprintf("* kubectl apply -f %s", extraFile),
@@ -93,12 +115,18 @@ func (m Model) View() string {
 	}
 
 	if m.ShowDependenciesFiles && selected.HaveDependenciesFiles() {
-		files := strings.Join(selected.DependenciesFilesList(), ",")
+		layers := selected.DependenciesLayers
+		str = append(str, "# Dependencies (apply in order, wait for Ready between each) :")
+		for _, layer := range layers {
+			str = append(str, fmt.Sprintf("* kubectl apply -f %s", strings.Join(layer, ",")))
+		}
+		str = append(str, "# Dependencies (delete in reverse order, wait for deletion between each) :")
+		for i := len(layers) - 1; i >= 0; i-- {
+			str = append(str, fmt.Sprintf("* kubectl delete -f %s", strings.Join(layers[i], ",")))
+		}
 		str = append(str,
 			"# Dependencies :",
-			fmt.Sprintf("* kubectl apply -f %s", files),
-			fmt.Sprintf("* kubectl delete -f %s", files),
-			fmt.Sprintf("* kubectl get -f %s", files),
+			fmt.Sprintf("* kubectl get -f %s", strings.Join(selected.DependenciesFilesList(), ",")),
 		)
 	}
 
@@ -115,6 +143,89 @@ func (k8sCmd *Cmd) JoinedFiles() string {
 	return strings.Join(k8sCmd.Files, ",")
 }
 
+// Executor returns the executor of the kubernetes commands, creating it on first use.
+func (m *Model) Executor() (kube.Executor, error) {
+	if m.executor != nil {
+		return m.executor, nil
+	}
+
+	executor, err := NewExecutor(m.config)
+	if err != nil {
+		return nil, err
+	}
+	m.executor = executor
+	return m.executor, nil
+}
+
+// NewExecutor returns a new executor of the kubernetes commands.
+// In debug mode, the commands run against a simulated cluster.
+func NewExecutor(c config.Provider) (kube.Executor, error) {
+	read, err := fileReader(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Debug {
+		simConfig := sim.Default()
+		if c.Viper != nil {
+			if err = c.Viper.UnmarshalKey("simulator", &simConfig); err != nil {
+				return nil, err
+			}
+		}
+		cluster := sim.New(simConfig)
+		cluster.SetFileReader(read)
+		return cluster, nil
+	}
+
+	client, err := kube.NewFromKubeconfig(c.Kubeconfig, c.KubeContext)
+	if err != nil {
+		return nil, err
+	}
+	client.SetFileReader(read)
+	return client, nil
+}
+
+// fileReader returns the reader of the applied files. Encrypted sidecar files are decrypted in memory
+// with the age keys of the config or the environment, then the files are rendered with the variables
+// of the active profile.
+func fileReader(c config.Provider) (kube.FileReader, error) {
+	keyring := secrets.LoadKeyring(c)
+	vars, err := c.ProfileVars()
+	if err != nil {
+		return nil, err
+	}
+	return render.Vars(vars).Reader(keyring.ReadFile), nil
+}
+
+// ReadObjects returns the objects of the files as rendered and decrypted by bean for the config.
+func ReadObjects(c config.Provider, files []string) ([]*unstructured.Unstructured, error) {
+	read, err := fileReader(c)
+	if err != nil {
+		return nil, err
+	}
+	return kube.ReadObjects(read, files)
+}
+
+// SetProfile switches the profile the files are rendered with.
+// The executor keeps its state, only its reader of files changes.
+func (m *Model) SetProfile(profile string) error {
+	m.config.Profile = profile
+	if m.executor == nil {
+		return nil
+	}
+
+	read, err := fileReader(m.config)
+	if err != nil {
+		return err
+	}
+	if e, ok := m.executor.(interface{ SetFileReader(kube.FileReader) }); ok {
+		e.SetFileReader(read)
+		return nil
+	}
+	m.executor = nil
+	return nil
+}
+
 // IsTickRunning returns true if the tick is running.
 func (m Model) IsTickRunning() bool {
 	return m.tickRunning
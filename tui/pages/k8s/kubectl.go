@@ -1,18 +1,23 @@
 package k8s
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"os/exec"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/FrangipaneTeam/bean/internal/kube"
 	"github.com/FrangipaneTeam/bean/tui/pages/errorpanel"
 )
 
+type cmdResult struct {
+	objects []*unstructured.Unstructured
+	err     error
+}
+
 // Kubectl runs a kubectl command.
 func Kubectl(ctx context.Context, k8sCmd *Cmd) tea.Cmd {
 	return func() tea.Msg {
@@ -26,56 +31,37 @@ func Kubectl(ctx context.Context, k8sCmd *Cmd) tea.Cmd {
 			}
 		}
 
-		var args []string
-		switch k8sCmd.Verb {
-		case "managed":
-			args = []string{"get", "managed"}
-		case "apply":
-			args = []string{k8sCmd.Verb, "-f", k8sCmd.JoinedFiles()}
-		case "delete":
-			args = []string{k8sCmd.Verb, "--wait=false", "-f", k8sCmd.JoinedFiles()}
+		if k8sCmd.Executor == nil {
+			return errorpanel.ErrorMsg{
+				Reason:   "no kubernetes executor",
+				Cause:    errors.New("k8sCmd.Executor == nil"),
+				CmdID:    k8sCmd.ID,
+				FromPage: k8sCmd.FromPage,
+			}
 		}
 
-		cmdChan := make(chan interface{})
+		cmdChan := make(chan cmdResult)
 		defer close(cmdChan)
 
 		go func() {
-			var cmd *exec.Cmd
-			if k8sCmd.Debug {
-				cmd = exec.CommandContext(ctx, "sleep", "10")
-			} else {
-				cmd = exec.CommandContext(ctx, "kubectl", args...)
-			}
-			var stdout, stderr bytes.Buffer
-			cmd.Stdout = &stdout
-			cmd.Stderr = &stderr
-			err := cmd.Run()
-			if err != nil {
-				if ctx.Err() == nil {
-					cmdChan <- errors.New(stderr.String())
-				}
-				return
-			}
+			res := run(ctx, k8sCmd)
 			if ctx.Err() == nil {
-				cmdChan <- stdout.String()
+				cmdChan <- res
 			}
 		}()
 
 		select {
-		case cmdResult := <-cmdChan:
-			switch result := cmdResult.(type) {
-			case error:
-				if result != nil {
-					return errorpanel.ErrorMsg{
-						Reason:   fmt.Sprintf("command kubectl %s failed", strings.Join(args, " ")),
-						Cause:    result,
-						CmdID:    k8sCmd.ID,
-						FromPage: k8sCmd.FromPage,
-					}
+		case res := <-cmdChan:
+			if res.err != nil {
+				return errorpanel.ErrorMsg{
+					Reason:   fmt.Sprintf("command %s failed", k8sCmd.Verb),
+					Cause:    res.err,
+					CmdID:    k8sCmd.ID,
+					FromPage: k8sCmd.FromPage,
 				}
-			case string:
-				k8sCmd.Result = result
 			}
+			k8sCmd.Objects = res.objects
+			k8sCmd.Result = result(k8sCmd.Verb, res.objects)
 
 		case <-ctx.Done():
 			return errorpanel.ErrorMsg{
@@ -88,3 +74,42 @@ func Kubectl(ctx context.Context, k8sCmd *Cmd) tea.Cmd {
 		return k8sCmd
 	}
 }
+
+func run(ctx context.Context, k8sCmd *Cmd) cmdResult {
+	var res cmdResult
+	switch k8sCmd.Verb {
+	case "managed":
+		res.objects, res.err = k8sCmd.Executor.Managed(ctx)
+	case "apply":
+		res.objects, res.err = kube.ApplyLayers(ctx, k8sCmd.Executor, k8sCmd.layers(), k8sCmd.Timeout, waitInterval)
+	case "delete":
+		res.objects, res.err = kube.DeleteLayers(ctx, k8sCmd.Executor, k8sCmd.layers(), k8sCmd.Timeout, waitInterval)
+	default:
+		res.err = fmt.Errorf("unknown verb %s", k8sCmd.Verb)
+	}
+	return res
+}
+
+func (k8sCmd *Cmd) layers() [][]string {
+	if len(k8sCmd.Layers) == 0 {
+		return [][]string{k8sCmd.Files}
+	}
+	return k8sCmd.Layers
+}
+
+// result renders the objects returned by a command.
+func result(verb string, objs []*unstructured.Unstructured) string {
+	if verb == "managed" {
+		return kube.FormatManaged(objs)
+	}
+
+	done := strings.TrimSuffix(verb, "e") + "ed"
+	if verb == "apply" {
+		done = "applied"
+	}
+	str := ""
+	for _, obj := range objs {
+		str += fmt.Sprintf("%s/%s %s\n", obj.GetKind(), obj.GetName(), done)
+	}
+	return str
+}
@@ -2,10 +2,16 @@ package k8s
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/charmbracelet/bubbles/progress"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/FrangipaneTeam/bean/config"
+	ex "github.com/FrangipaneTeam/bean/internal/exlist"
 	"github.com/FrangipaneTeam/bean/internal/keymap"
+	"github.com/FrangipaneTeam/bean/internal/kube"
 	"github.com/FrangipaneTeam/bean/tui/pages/common"
 	"github.com/FrangipaneTeam/bean/tui/pages/exlist"
 )
@@ -24,6 +30,8 @@ type Model struct {
 	GetProgress           progress.Model
 	ShowDependenciesFiles bool
 	common                *common.Model
+	config                config.Provider
+	executor              kube.Executor
 }
 
 type Message struct {
@@ -32,24 +40,50 @@ type Message struct {
 }
 
 type Cmd struct {
-	ID       string
-	Done     bool
-	Verb     string
-	Files    []string
+	ID    string
+	Done  bool
+	Verb  string
+	Files []string
+	// Layers are the files in apply order, Files when empty.
+	Layers   [][]string
 	Kind     string
 	Result   string
-	Cancel   context.CancelFunc
-	FromPage common.PageID
-	Debug    bool
+	Objects  []*unstructured.Unstructured
+	Executor kube.Executor
+	// Example is the example the command was run for.
+	Example *ex.Example
+	// Resources is the readiness of the managed resources applied by the command.
+	Resources []kube.ResourceStatus
+	Timeout   time.Duration
+	waitCtx   context.Context
+	stopWait  context.CancelFunc
+	Cancel    context.CancelFunc
+	FromPage  common.PageID
+}
+
+// NewCmd returns the command of the verb on the files of the example, after its dependencies if asked.
+func NewCmd(e *ex.Example, verb string, withDependencies bool, timeout time.Duration) (*Cmd, error) {
+	if withDependencies && e.DependenciesErr != nil {
+		return nil, fmt.Errorf("can't order the dependencies: %w", e.DependenciesErr)
+	}
+	return &Cmd{
+		Verb:    verb,
+		Files:   e.Files(withDependencies),
+		Layers:  e.FileLayers(withDependencies),
+		Kind:    e.Desc,
+		Example: e,
+		Timeout: timeout,
+	}, nil
 }
 
 // New returns a new model of the k8s page.
-func New(keymap *keymap.ListKeyMap, common *common.Model, pages *exlist.Model) *Model {
+func New(keymap *keymap.ListKeyMap, common *common.Model, pages *exlist.Model, c config.Provider) *Model {
 	cmdList := make(map[string]*Cmd)
 	return &Model{
 		keys:                  keymap,
 		pages:                 pages,
 		common:                common,
+		config:                c,
 		CmdList:               cmdList,
 		ShowDependenciesFiles: true,
 		GetProgress: progress.New(
@@ -0,0 +1,57 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FrangipaneTeam/bean/internal/exlist"
+	"github.com/FrangipaneTeam/bean/internal/secrets"
+)
+
+// KubectlCommands returns the kubectl commands doing the verb on the files of the example, as shown by the print page.
+// The layers of the dependencies are applied in order and deleted in reverse order, the files are got at once.
+func KubectlCommands(e *exlist.Example, verb string, withDependencies bool) []string {
+	layers := e.FileLayers(withDependencies)
+	switch verb {
+	case "get":
+		layers = [][]string{e.Files(withDependencies)}
+	case "delete":
+		reversed := make([][]string, 0, len(layers))
+		for i := len(layers) - 1; i >= 0; i-- {
+			reversed = append(reversed, layers[i])
+		}
+		layers = reversed
+	}
+
+	// an encrypted secret file is decrypted in memory by bean, kubectl reads it from the decrypt command
+	encrypted := ""
+	if e.HaveSecretFile() && e.SecretEncryption != "" {
+		encrypted = e.SecretFile
+	}
+
+	commands := []string{}
+	for _, layer := range layers {
+		files := make([]string, 0, len(layer))
+		secret := false
+		for _, f := range layer {
+			if f == encrypted {
+				secret = true
+				continue
+			}
+			files = append(files, f)
+		}
+		commands = append(commands, fmt.Sprintf("kubectl %s -f %s", verb, strings.Join(files, ",")))
+		if secret {
+			commands = append(commands, fmt.Sprintf("%s | kubectl %s -f -", decryptCommand(e), verb))
+		}
+	}
+	return commands
+}
+
+// decryptCommand returns the command decrypting the secret file of the example to stdout.
+func decryptCommand(e *exlist.Example) string {
+	if e.SecretEncryption == string(secrets.FormatAge) {
+		return fmt.Sprintf("age -d -i $%s %s", secrets.EnvAgeKeyFile, e.SecretFile)
+	}
+	return fmt.Sprintf("sops -d %s", e.SecretFile)
+}
@@ -0,0 +1,92 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/FrangipaneTeam/bean/internal/kube"
+)
+
+const waitInterval = 2 * time.Second
+
+// ReadyMsg holds the readiness of the resources applied by a command.
+type ReadyMsg struct {
+	Cmd       *Cmd
+	Resources []kube.ResourceStatus
+	TimedOut  bool
+	Err       error
+}
+
+// WaitReady starts waiting for the managed resources applied by the command to be Ready and Synced.
+// It returns nil if the command did not apply any managed resource.
+func WaitReady(k8sCmd *Cmd) tea.Cmd {
+	managed := kube.ManagedObjects(k8sCmd.Objects)
+	if len(managed) == 0 {
+		return nil
+	}
+
+	k8sCmd.Resources = make([]kube.ResourceStatus, 0, len(managed))
+	for _, obj := range managed {
+		k8sCmd.Resources = append(k8sCmd.Resources, kube.ResourceStatus{Kind: obj.GetKind(), Name: obj.GetName()})
+	}
+	k8sCmd.waitCtx, k8sCmd.stopWait = context.WithTimeout(context.Background(), k8sCmd.Timeout)
+
+	return k8sCmd.PollReady()
+}
+
+// PollReady returns the readiness of the managed resources applied by the command after the wait interval.
+func (k8sCmd *Cmd) PollReady() tea.Cmd {
+	ctx := k8sCmd.waitCtx
+	return tea.Tick(waitInterval, func(time.Time) tea.Msg {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil
+		}
+
+		msg := ReadyMsg{Cmd: k8sCmd}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			msg.TimedOut = true
+			msg.Resources = k8sCmd.Resources
+			return msg
+		}
+
+		msg.Resources, msg.Err = kube.Statuses(ctx, k8sCmd.Executor, kube.ManagedObjects(k8sCmd.Objects))
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			msg.TimedOut = true
+			msg.Resources, msg.Err = k8sCmd.Resources, nil
+		}
+		return msg
+	})
+}
+
+// StopWait stops waiting for the resources applied by the command.
+func (k8sCmd *Cmd) StopWait() {
+	if k8sCmd.stopWait != nil {
+		k8sCmd.stopWait()
+	}
+}
+
+// Progress returns the readiness progress of the command, and true if it failed: a resource is still not
+// ready at the timeout.
+func (k8sCmd *Cmd) Progress(timedOut bool) (string, bool) {
+	done := kube.CountDone(k8sCmd.Resources)
+	total := len(k8sCmd.Resources)
+
+	r, pending := kube.NotReady(k8sCmd.Resources)
+	switch {
+	case !pending:
+		return fmt.Sprintf("ready %d/%d", done, total), false
+	case timedOut:
+		return fmt.Sprintf("%s not ready after %s", r.Pending(), k8sCmd.Timeout), true
+	}
+
+	// the message of a false condition is progress until the timeout, a reference not resolved yet for instance
+	progress := fmt.Sprintf("waiting ready %d/%d", done, total)
+	if r.Message != "" {
+		progress += ", " + r.Pending()
+	}
+	return progress, false
+}
@@ -1,80 +1,106 @@
 package md
 
 import (
-	"log"
+	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/FrangipaneTeam/bean/config"
+	"github.com/FrangipaneTeam/bean/internal/coverage"
 	"github.com/FrangipaneTeam/bean/internal/crd"
 	"github.com/FrangipaneTeam/bean/internal/examples"
 	"github.com/FrangipaneTeam/bean/internal/exlist"
-	"github.com/FrangipaneTeam/bean/internal/template"
+	"github.com/FrangipaneTeam/bean/internal/results"
 	"github.com/FrangipaneTeam/bean/tui/pages/errorpanel"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// ListTestedFile is the file of the markdown list of tested resources, in the provider path.
+const ListTestedFile = "list-tested.md"
+
 type testedStruct struct {
 	crd.CRD
 	Tested bool
+	// Examples are the example files applying the kind.
+	Examples []string
+	// VersionExamples are the example files applying each version of the kind.
+	VersionExamples map[string][]string
+	// UsedFields are the forProvider and initProvider fields set by the examples.
+	UsedFields map[string]bool
 }
 
 type listTestedStruct map[string]map[string]testedStruct
 
-// GenerateListTested generates the list of tested CRDs.
+// GenerateListTested generates the markdown list of tested CRDs.
 func GenerateListTested(c config.Provider) tea.Cmd {
 	return func() tea.Msg {
-		crdS, err := crd.GetCRDs(c.Path + "/package/crds")
+		report, err := ListTested(c)
 		if err != nil {
-			return errorpanel.ErrorMsg{Reason: "can't get crds", Cause: err}
+			return errorpanel.ErrorMsg{Reason: "can't generate the list of tested resources", Cause: err}
 		}
-		examples := examples.GenerateExamplesList(c)
-		switch ex := examples.(type) {
-		case errorpanel.ErrorMsg:
-			log.Print(ex.Cause.Error())
-			os.Exit(1)
 
-		case exlist.LoadedExamples:
+		f, err := os.Create(filepath.Join(c.Path, ListTestedFile))
+		if err != nil {
+			return errorpanel.ErrorMsg{Reason: "can't write the list of tested resources", Cause: err}
+		}
+		defer f.Close()
 
-			data := &listTestedStruct{}
-			data.Init()
+		if err = coverage.Write(f, coverage.FormatMarkdown, report); err != nil {
+			return errorpanel.ErrorMsg{Reason: "can't write the list of tested resources", Cause: err}
+		}
+		return exlist.ListTestedDone{}
+	}
+}
 
-			for _, val := range crdS {
-				data.Add(val)
-			}
+// ListTested returns the coverage of the CRDs of the provider by its examples.
+func ListTested(c config.Provider) (coverage.Report, error) {
+	crdsRoots, err := c.CRDsRoots()
+	if err != nil {
+		return coverage.Report{}, fmt.Errorf("can't get crds: %w", err)
+	}
+	crdS, err := crd.GetCRDs(crdsRoots...)
+	if err != nil {
+		return coverage.Report{}, fmt.Errorf("can't get crds: %w", err)
+	}
 
-			for k, v := range ex.Examples {
-				if k == "-" {
-					continue
-				}
-				for _, val := range v {
-					e, ok := val.(*exlist.Example)
-					if ok {
-						apiVersion := strings.Split(e.APIVersion, "/")
-						data.CheckIfTested(apiVersion[0], e.Kind)
-					}
-				}
-			}
+	var loaded exlist.LoadedExamples
+	switch ex := examples.GenerateExamplesList(c).(type) {
+	case errorpanel.ErrorMsg:
+		return coverage.Report{}, fmt.Errorf("%s: %w", ex.Reason, ex.Cause)
+	case *errorpanel.ErrorMsg:
+		return coverage.Report{}, fmt.Errorf("%s: %w", ex.Reason, ex.Cause)
+	case exlist.LoadedExamples:
+		loaded = ex
+	}
+
+	data := &listTestedStruct{}
+	data.Init()
+
+	for _, val := range crdS {
+		data.Add(val)
+	}
 
-			markdownTemplate := `
-# List of tested resources
-
-{{ range $group, $kinds := . }}
-## {{ $group }}
-| Kind | Tested |
-| ---- | ------ |{{ range $kind, $crd := $kinds }}
-| {{ $kind }} | {{ if $crd.Tested }} :white_check_mark: {{else}} :x: {{end}} |{{ end }}
-{{ end }}
-`
-
-			template.Process(
-				markdownTemplate,
-				c.Path+"/list-tested.md",
-				data,
-			)
+	for _, e := range loaded.List() {
+		file, errRel := filepath.Rel(c.Path, e.FullPath)
+		if errRel != nil {
+			file = e.FullPath
 		}
-		return exlist.ListTestedDone{}
+		for _, r := range e.Resources {
+			group, version, _ := strings.Cut(r.APIVersion, "/")
+			data.CheckIfTested(group, version, r.Kind, file)
+			data.UseFields(group, r.Kind, r.Spec)
+		}
+	}
+
+	last, err := results.Last(c.Path)
+	if err != nil {
+		return coverage.Report{}, fmt.Errorf("can't read the results: %w", err)
 	}
+	report := data.Report()
+	report.SetRuns(last)
+	return report, nil
 }
 
 // Init initializes the list.
@@ -94,17 +120,106 @@ func (l *listTestedStruct) Add(c crd.CRD) {
 	}
 
 	(*l)[c.Spec.Group][c.Spec.Names.Kind] = testedStruct{
-		CRD:    c,
-		Tested: false,
+		CRD:             c,
+		Tested:          false,
+		VersionExamples: map[string][]string{},
+		UsedFields:      map[string]bool{},
 	}
 }
 
-// CheckIfTested checks if the resource is tested.
-func (l *listTestedStruct) CheckIfTested(group, kind string) {
+// CheckIfTested checks if the resource is tested and records the example file applying its version.
+func (l *listTestedStruct) CheckIfTested(group, version, kind, file string) {
 	if _, isMap := (*l)[group]; isMap {
 		if entry, ok := (*l)[group][kind]; ok {
 			entry.Tested = true
+			entry.Examples = appendFile(entry.Examples, file)
+			entry.VersionExamples[version] = appendFile(entry.VersionExamples[version], file)
 			(*l)[group][kind] = entry
 		}
 	}
 }
+
+// appendFile appends the file unless it is the last one, the resources of a file are checked in a row.
+func appendFile(files []string, file string) []string {
+	if len(files) > 0 && files[len(files)-1] == file {
+		return files
+	}
+	return append(files, file)
+}
+
+// UseFields records the forProvider and initProvider fields set by the spec of a resource.
+func (l *listTestedStruct) UseFields(group, kind string, spec map[string]interface{}) {
+	if entry, ok := (*l)[group][kind]; ok {
+		coverage.UsedFields(spec, entry.UsedFields)
+	}
+}
+
+// Report returns the list as a coverage report sorted by group and kind.
+func (l listTestedStruct) Report() coverage.Report {
+	report := coverage.Report{Kinds: []coverage.Kind{}}
+
+	groups := make([]string, 0, len(l))
+	for group := range l {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	for _, group := range groups {
+		kinds := make([]string, 0, len(l[group]))
+		for kind := range l[group] {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+
+		for _, kind := range kinds {
+			entry := l[group][kind]
+			examples := append([]string{}, entry.Examples...)
+			sort.Strings(examples)
+			versions, deprecatedOnly := entry.versionCoverage()
+
+			report.Kinds = append(report.Kinds, coverage.Kind{
+				Group:           group,
+				Kind:            kind,
+				Versions:        entry.VersionNames(),
+				Tested:          entry.Tested,
+				Examples:        examples,
+				VersionCoverage: versions,
+				DeprecatedOnly:  deprecatedOnly,
+				Fields:          coverage.NewFields(entry.SpecSchema(), entry.UsedFields),
+			})
+			report.Total++
+			if entry.Tested {
+				report.Tested++
+			}
+		}
+	}
+	return report
+}
+
+// versionCoverage returns the coverage of the versions of the CRD, and true if the examples
+// only apply deprecated versions.
+func (t testedStruct) versionCoverage() ([]coverage.Version, bool) {
+	versions := make([]coverage.Version, 0, len(t.Spec.Versions))
+	storage := t.StorageVersion()
+	testedDeprecated, testedCurrent := false, false
+	for _, v := range t.Spec.Versions {
+		examples := append([]string{}, t.VersionExamples[v.Name]...)
+		sort.Strings(examples)
+
+		versions = append(versions, coverage.Version{
+			Name:       v.Name,
+			Storage:    storage != nil && storage.Name == v.Name,
+			Deprecated: v.Deprecated,
+			Tested:     len(examples) > 0,
+			Examples:   examples,
+		})
+		switch {
+		case len(examples) == 0:
+		case v.Deprecated:
+			testedDeprecated = true
+		default:
+			testedCurrent = true
+		}
+	}
+	return versions, testedDeprecated && !testedCurrent
+}
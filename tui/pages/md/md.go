@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/FrangipaneTeam/bean/config"
+	"github.com/FrangipaneTeam/bean/internal/coverage"
 	"github.com/FrangipaneTeam/bean/internal/keymap"
 	"github.com/FrangipaneTeam/bean/tui/pages/common"
 )
@@ -22,6 +23,8 @@ type Model struct {
 	keys     *keymap.ListKeyMap
 	config   config.Provider
 	common   *common.Model
+	// report is the coverage of the last rendered fields coverage.
+	report coverage.Report
 }
 
 // Init initializes the model.
@@ -76,10 +79,25 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 				return m, cmd
 			}
 
+		case key.Matches(msg, m.keys.ShowFieldsCoverage):
+			m.keys.EnableCoverageKeys()
+			if m.common.GetViewName() == common.PRoot {
+				cmd = renderFieldsCoverage(m.config, common.Width)
+
+				return m, cmd
+			}
+
 		case key.Matches(msg, m.keys.GenerateListTested):
 			cmd = GenerateListTested(m.config)
 			return m, cmd
 		}
+	case fieldsCoverage:
+		m.report = msg.report
+		m.common.SetViewName(common.PCoverage)
+		m.Viewport.SetContent(msg.content)
+		m.Viewport.GotoTop()
+		return m, nil
+
 	case markdown:
 		m.common.SetViewName(common.PViewPort)
 		m.Viewport.SetContent(msg.content)
@@ -95,13 +113,22 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 	}
 
 	if m.common.GetViewName() == common.PViewPort ||
-		m.common.GetViewName() == common.PPrintActions {
+		m.common.GetViewName() == common.PPrintActions ||
+		m.common.GetViewName() == common.PGraph ||
+		m.common.GetViewName() == common.PDiagnostics ||
+		m.common.GetViewName() == common.PCoverage ||
+		m.common.GetViewName() == common.PCRDChanges {
 		m.Viewport, cmd = m.Viewport.Update(msg)
 		cmds = append(cmds, cmd)
 	}
 	return m, tea.Batch(cmds...)
 }
 
+// Report returns the coverage of the last rendered fields coverage.
+func (m Model) Report() coverage.Report {
+	return m.report
+}
+
 // View renders the model.
 func (m Model) View() string {
 	return ""
@@ -1,8 +1,11 @@
 package md
 
 import (
+	"bytes"
 	"os"
 
+	"github.com/FrangipaneTeam/bean/config"
+	"github.com/FrangipaneTeam/bean/internal/coverage"
 	"github.com/FrangipaneTeam/bean/tui/pages/errorpanel"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
@@ -13,6 +16,12 @@ type markdown struct {
 	content string
 }
 
+// fieldsCoverage is the rendered coverage of the fields and its report.
+type fieldsCoverage struct {
+	markdown
+	report coverage.Report
+}
+
 // renderMarkdown renders a markdown file.
 func renderMarkdown(file string, wrap int) tea.Cmd {
 	return func() tea.Msg {
@@ -23,27 +32,57 @@ func renderMarkdown(file string, wrap int) tea.Cmd {
 				Cause:  err,
 			}
 		}
+		return render(string(f), wrap)
+	}
+}
 
-		renderer, err := glamour.NewTermRenderer(
-			glamour.WithAutoStyle(),
-			glamour.WithWordWrap(wrap),
-			glamour.WithStylePath("dracula"),
-		)
+// renderFieldsCoverage renders the list of tested resources with the coverage of their fields,
+// generated from the current CRDs and examples.
+func renderFieldsCoverage(c config.Provider, wrap int) tea.Cmd {
+	return func() tea.Msg {
+		report, err := ListTested(c)
 		if err != nil {
 			return errorpanel.ErrorMsg{
-				Reason: "new render markdown failed",
+				Reason: "can't generate the list of tested resources",
 				Cause:  err,
 			}
 		}
-		str, err := renderer.Render(string(f))
-		if err != nil {
+
+		var b bytes.Buffer
+		if err = coverage.Write(&b, coverage.FormatMarkdown, report); err != nil {
 			return errorpanel.ErrorMsg{
-				Reason: "render markdown failed",
+				Reason: "can't generate the list of tested resources",
 				Cause:  err,
 			}
 		}
-		return markdown{
-			content: str,
+		msg := render(b.String(), wrap)
+		if md, ok := msg.(markdown); ok {
+			return fieldsCoverage{markdown: md, report: report}
 		}
+		return msg
+	}
+}
+
+func render(content string, wrap int) tea.Msg {
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithAutoStyle(),
+		glamour.WithWordWrap(wrap),
+		glamour.WithStylePath("dracula"),
+	)
+	if err != nil {
+		return errorpanel.ErrorMsg{
+			Reason: "new render markdown failed",
+			Cause:  err,
+		}
+	}
+	str, err := renderer.Render(content)
+	if err != nil {
+		return errorpanel.ErrorMsg{
+			Reason: "render markdown failed",
+			Cause:  err,
+		}
+	}
+	return markdown{
+		content: str,
 	}
 }
@@ -0,0 +1,116 @@
+// Package untested provides the list of the kinds applied by no example.
+package untested
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/FrangipaneTeam/bean/config"
+	"github.com/FrangipaneTeam/bean/internal/coverage"
+	"github.com/FrangipaneTeam/bean/internal/scaffold"
+	"github.com/FrangipaneTeam/bean/tui/pages/common"
+	"github.com/FrangipaneTeam/bean/tui/pages/errorpanel"
+)
+
+// statusMessageLifetime is the time the path of a scaffolded example is shown.
+const statusMessageLifetime = 5 * time.Second
+
+// Item is an untested kind.
+type Item struct {
+	Group string
+	Kind  string
+}
+
+// Title returns the kind.
+func (i Item) Title() string { return i.Kind }
+
+// Description returns the group of the kind.
+func (i Item) Description() string { return i.Group }
+
+// FilterValue returns the kind.
+func (i Item) FilterValue() string { return i.Kind }
+
+// Model is the model of the list of untested kinds.
+type Model struct {
+	List list.Model
+}
+
+// New returns the list of untested kinds.
+func New(w, h int) *Model {
+	l := list.New([]list.Item{}, list.NewDefaultDelegate(), w, h)
+	l.Title = "Untested kinds, ↲ to scaffold an example"
+	l.DisableQuitKeybindings()
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(false)
+	l.SetStatusBarItemName("kind", "kinds")
+	l.StatusMessageLifetime = statusMessageLifetime
+	return &Model{List: l}
+}
+
+// SetReport lists the untested kinds of the report.
+func (m *Model) SetReport(r coverage.Report) tea.Cmd {
+	items := []list.Item{}
+	for _, k := range r.Kinds {
+		if !k.Tested {
+			items = append(items, Item{Group: k.Group, Kind: k.Kind})
+		}
+	}
+	m.List.ResetSelected()
+	return m.List.SetItems(items)
+}
+
+// Selected returns the selected kind.
+func (m Model) Selected() (Item, bool) {
+	i, ok := m.List.SelectedItem().(Item)
+	return i, ok
+}
+
+// Remove removes the kind, once scaffolded. The selection may have moved since the scaffold started.
+func (m *Model) Remove(i Item) {
+	for index, item := range m.List.Items() {
+		if item == i {
+			m.List.RemoveItem(index)
+			return
+		}
+	}
+}
+
+// Update updates the list.
+func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.List, cmd = m.List.Update(msg)
+	return m, cmd
+}
+
+// View renders the list.
+func (m Model) View() string {
+	return m.List.View()
+}
+
+// SetSize sets the size of the list.
+func (m *Model) SetSize(w, h int) {
+	m.List.SetSize(w, h)
+}
+
+// ScaffoldedMsg is sent when the example of a kind is scaffolded.
+type ScaffoldedMsg struct {
+	Item Item
+	Path string
+}
+
+// Scaffold writes the example of the kind in the examples of the provider.
+func Scaffold(c config.Provider, i Item) tea.Cmd {
+	return func() tea.Msg {
+		path, err := scaffold.Kind(c, i.Group+"/"+i.Kind)
+		if err != nil {
+			return errorpanel.ErrorMsg{
+				Reason:   "can't scaffold the example of " + i.Kind,
+				Cause:    err,
+				FromPage: common.PUntested,
+			}
+		}
+		return ScaffoldedMsg{Item: i, Path: path}
+	}
+}
@@ -0,0 +1,29 @@
+package untested
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/FrangipaneTeam/bean/internal/coverage"
+)
+
+func TestRemove(t *testing.T) {
+	m := New(80, 20)
+	m.SetReport(coverage.Report{Kinds: []coverage.Kind{
+		{Group: "ec2.aws.upbound.io", Kind: "Subnet"},
+		{Group: "ec2.aws.upbound.io", Kind: "VPC", Tested: true},
+		{Group: "ec2.aws.upbound.io", Kind: "VPCEndpoint"},
+		{Group: "s3.aws.upbound.io", Kind: "Bucket"},
+	}})
+	// the cursor moved while the first kind was scaffolded
+	m.List.Select(2)
+
+	m.Remove(Item{Group: "ec2.aws.upbound.io", Kind: "Subnet"})
+	got := []string{}
+	for _, i := range m.List.Items() {
+		got = append(got, i.(Item).Kind)
+	}
+	if want := []string{"VPCEndpoint", "Bucket"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+}